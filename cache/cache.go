@@ -0,0 +1,232 @@
+// Package cache persists the results of the stages of a run (parsing, analyses) as gob
+// snapshots, to load them instead of computing them again when their inputs did not change
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// formatVersion is the version of the snapshot files (bump it when their layout changes)
+const formatVersion = 1
+
+// snapshotsPerStage is the number of snapshots kept per stage and name, the least recently used
+// ones being removed (e.g., the analyses of a corpus with several cost models)
+const snapshotsPerStage = 4
+
+// Stage is a stage of a run, cached separately
+type Stage string
+
+const (
+	ParseStage    Stage = "parse"    // stacks and cards parsed into metadata
+	AnalysisStage Stage = "analysis" // metadata analyzed (node attributes and statistics)
+)
+
+// Cache stores the snapshots of the stages in a directory
+type Cache struct {
+	dir     string
+	version string // DeMystify build (see buildVersion)
+}
+
+// header identifies the content of a snapshot file
+type header struct {
+	Format int
+	Key    string
+}
+
+// New returns a cache storing its snapshots in the given directory
+func New(dir string) (*Cache, error) {
+	version, err := buildVersion()
+	if err != nil {
+		return nil, fmt.Errorf("unable to identify the DeMystify build: %w", err)
+	}
+
+	return &Cache{dir: dir, version: version}, nil
+}
+
+// buildVersion identifies the DeMystify build by the hash of its executable
+// NOTE: unlike a version number, any change of the code invalidates the snapshots
+// (including with `go run`, whose executables are rebuilt)
+func buildVersion() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Key returns the key of a stage: the hash of the DeMystify build and of the given parts
+// (encoded as JSON), e.g., the hash of the input files and the options of the stage
+func (c *Cache) Key(parts ...any) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(c.version))
+
+	for _, part := range parts {
+		content, err := json.Marshal(part)
+		if err != nil {
+			return "", err
+		}
+		hash.Write(content)
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// HashFiles returns the hash of the files of a directory (relative paths and contents)
+func HashFiles(root string) (string, error) {
+	var paths []string
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	slices.Sort(paths)
+
+	hash := sha256.New()
+	for _, path := range paths {
+		relativePath, err := filepath.Rel(root, path)
+		if err != nil {
+			return "", err
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.ToSlash(relativePath), len(content))
+		hash.Write(content)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Load decodes the snapshot of a stage into value, and reports whether it is valid
+// (same key); a missing, outdated, or corrupted snapshot is a cache miss
+func (c *Cache) Load(stage Stage, name, key string, value any) bool {
+	path := c.path(stage, name, key)
+	file, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer file.Close()
+
+	decoder := gob.NewDecoder(file)
+
+	var h header
+	if err := decoder.Decode(&h); err != nil || h.Format != formatVersion || h.Key != key {
+		return false
+	}
+	if decoder.Decode(value) != nil {
+		return false
+	}
+
+	// NOTE: the modification time orders the snapshots by last use (see prune)
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	return true
+}
+
+// Save stores the snapshot of a stage, and removes the least recently used snapshots
+// of the stage and name beyond snapshotsPerStage
+func (c *Cache) Save(stage Stage, name, key string, value any) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	// NOTE: written to a temporary file first, so that an interrupted run
+	// does not leave a truncated snapshot
+	file, err := os.CreateTemp(c.dir, string(stage)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	encoder := gob.NewEncoder(file)
+	if err := encoder.Encode(header{Format: formatVersion, Key: key}); err != nil {
+		file.Close()
+		return err
+	}
+	if err := encoder.Encode(value); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(file.Name(), c.path(stage, name, key)); err != nil {
+		return err
+	}
+	return c.prune(stage, name)
+}
+
+// prune removes the least recently used snapshots of a stage and name beyond snapshotsPerStage
+func (c *Cache) prune(stage Stage, name string) error {
+	paths, err := filepath.Glob(filepath.Join(c.dir, fmt.Sprintf("%s-%s-*.gob", stage, name)))
+	if err != nil {
+		return err
+	}
+
+	type snapshot struct {
+		path    string
+		modTime time.Time
+	}
+
+	snapshots := make([]snapshot, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue // removed in the meantime
+		}
+		snapshots = append(snapshots, snapshot{path: path, modTime: info.ModTime()})
+	}
+
+	// most recently used first
+	slices.SortFunc(snapshots, func(a, b snapshot) int {
+		return b.modTime.Compare(a.modTime)
+	})
+
+	for _, s := range snapshots[min(snapshotsPerStage, len(snapshots)):] {
+		if err := os.Remove(s.path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// path returns the path of the snapshot of a stage
+// NOTE: one snapshot per stage, name (e.g., per corpus), and key, so that the runs with other
+// options (e.g., cost models) do not replace it
+func (c *Cache) path(stage Stage, name, key string) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%s-%s-%s.gob", stage, name, key[:min(len(key), 16)]))
+}
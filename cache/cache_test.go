@@ -0,0 +1,79 @@
+package cache
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestSnapshotsByKey(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// e.g., the analyses of a corpus with two cost models, used alternately
+	clicks, ageSwitches := "clicks-key", "age-switches-key"
+	for _, key := range []string{clicks, ageSwitches} {
+		if err := c.Save(AnalysisStage, "corpus", key, key+"-value"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, key := range []string{clicks, ageSwitches, clicks} {
+		var value string
+		if !c.Load(AnalysisStage, "corpus", key, &value) || value != key+"-value" {
+			t.Errorf("%s: got a cache miss (%q)", key, value)
+		}
+	}
+
+	var value string
+	if c.Load(AnalysisStage, "corpus", "other-key", &value) {
+		t.Error("other-key: got a cache hit")
+	}
+	if c.Load(ParseStage, "corpus", clicks, &value) {
+		t.Error("parse stage: got a cache hit")
+	}
+}
+
+func TestSnapshotsPruning(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := make([]string, snapshotsPerStage)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d", i)
+		if err := c.Save(AnalysisStage, "corpus", keys[i], i); err != nil {
+			t.Fatal(err)
+		}
+
+		// the first snapshot is the oldest one
+		past := time.Now().Add(time.Duration(i-len(keys)) * time.Hour)
+		if err := os.Chtimes(c.path(AnalysisStage, "corpus", keys[i]), past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// using the oldest snapshot makes the second one the least recently used
+	var value int
+	if !c.Load(AnalysisStage, "corpus", keys[0], &value) {
+		t.Fatal("key-0: got a cache miss")
+	}
+	if err := c.Save(AnalysisStage, "corpus", "new-key", 0); err != nil {
+		t.Fatal(err)
+	}
+	// another stage or name is not pruned with them
+	if err := c.Save(ParseStage, "corpus", "new-key", 0); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range append(keys, "new-key") {
+		_, err := os.Stat(c.path(AnalysisStage, "corpus", key))
+		if removed := os.IsNotExist(err); removed != (key == keys[1]) {
+			t.Errorf("%s: removed = %v", key, removed)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/parser"
+)
+
+// ParseSnapshot is the result of the parse stage
+type ParseSnapshot struct {
+	Metadata  *common.Metadata
+	Errors    []common.ErrorEntry // errors skipped in the "continue on error" mode
+	Integrity *parser.IntegrityReport
+	DeadLinks *parser.DeadLinkReport
+}
+
+// AnalysisSnapshot is the result of the analysis stage: the metadata with the node
+// attributes and the statistics computed by the analyses
+type AnalysisSnapshot struct {
+	Metadata *common.Metadata
+}
+
+// ErrorReport returns the report of the errors skipped while parsing
+func (s *ParseSnapshot) ErrorReport() *common.ErrorReport {
+	report := &common.ErrorReport{}
+	for _, entry := range s.Errors {
+		report.Add(entry)
+	}
+	return report
+}
+
+// restore fixes the values changed by the gob encoding: the edges reference copies
+// of their nodes (instead of the nodes of the metadata), and the empty lists are nil
+func (s *ParseSnapshot) restore() {
+	relink(s.Metadata)
+
+	if s.Integrity.Issues == nil {
+		s.Integrity.Issues = []parser.IntegrityIssue{}
+	}
+	if s.DeadLinks.Stacks == nil {
+		s.DeadLinks.Stacks = []parser.DeadLinkGroup{}
+	}
+}
+
+// relink makes the edges reference the nodes of the metadata (matched by name)
+func relink(metadata *common.Metadata) {
+	nodes := make(map[string]*common.Node, len(metadata.Nodes))
+	for _, node := range metadata.Nodes {
+		nodes[node.Name] = node
+	}
+
+	for _, edge := range metadata.Edges {
+		edge.Source = nodes[edge.Source.Name]
+		edge.Target = nodes[edge.Target.Name]
+	}
+}
+
+// LoadParse loads the snapshot of the parse stage, if valid
+func (c *Cache) LoadParse(name, key string) (*ParseSnapshot, bool) {
+	var snapshot ParseSnapshot
+	if !c.Load(ParseStage, name, key, &snapshot) || snapshot.Metadata == nil ||
+		snapshot.Integrity == nil || snapshot.DeadLinks == nil {
+		return nil, false
+	}
+
+	snapshot.restore()
+	return &snapshot, true
+}
+
+// LoadAnalysis loads the snapshot of the analysis stage, if valid
+func (c *Cache) LoadAnalysis(name, key string) (*AnalysisSnapshot, bool) {
+	var snapshot AnalysisSnapshot
+	if !c.Load(AnalysisStage, name, key, &snapshot) || snapshot.Metadata == nil {
+		return nil, false
+	}
+
+	relink(snapshot.Metadata)
+	return &snapshot, true
+}
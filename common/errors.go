@@ -1,8 +1,123 @@
 package common
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	NodeAlreadyExistsErr = errors.New("node already exists")
 	NodeNotFoundErr      = errors.New("node not found")
+	NodeIDCollisionErr   = errors.New("node ID collision")
+	QuerySyntaxErr       = errors.New("query syntax error")
 )
+
+// ParseError is an error located in a corpus file
+type ParseError struct {
+	File    string
+	Line    int    // line in the file (XML errors) or in the script (script errors); 0 if unknown
+	Context string // offending script line, if any
+	Err     error
+}
+
+func (e *ParseError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s:%d", e.File, e.Line)
+	}
+
+	if e.Context != "" {
+		return fmt.Sprintf("%s: %v (in %q)", location, e.Err, e.Context)
+	}
+	return fmt.Sprintf("%s: %v", location, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// UnknownStackError is returned when a script references a stack that does not exist
+type UnknownStackError struct {
+	Stack string
+}
+
+func (e *UnknownStackError) Error() string {
+	return fmt.Sprintf("unknown stack %q", e.Stack)
+}
+
+// ErrorReport collects the errors encountered in the "continue on error" mode
+type ErrorReport struct {
+	errs []error
+}
+
+// ErrorEntry is the structured representation of a collected error
+type ErrorEntry struct {
+	Kind    string `json:"kind"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Stack   string `json:"stack,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error returns the message of the entry, so that an entry can be recorded again
+// in a report (e.g., loaded from a cache)
+func (e ErrorEntry) Error() string {
+	return e.Message
+}
+
+// Add records an error (nil errors are ignored)
+func (r *ErrorReport) Add(err error) {
+	if err != nil {
+		r.errs = append(r.errs, err)
+	}
+}
+
+// HasErrors reports whether any error has been recorded
+func (r *ErrorReport) HasErrors() bool {
+	return len(r.errs) > 0
+}
+
+// Errors returns the recorded errors
+func (r *ErrorReport) Errors() []error {
+	return r.errs
+}
+
+// Err returns the recorded errors joined, or nil
+func (r *ErrorReport) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// Entries returns the recorded errors as structured entries
+func (r *ErrorReport) Entries() []ErrorEntry {
+	entries := make([]ErrorEntry, 0, len(r.errs))
+
+	for _, err := range r.errs {
+		// entries restored as errors (e.g., from a cache)
+		if entry, ok := err.(ErrorEntry); ok {
+			entries = append(entries, entry)
+			continue
+		}
+
+		entry := ErrorEntry{
+			Kind:    "error",
+			Message: err.Error(),
+		}
+
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) {
+			entry.Kind = "parse"
+			entry.File = parseErr.File
+			entry.Line = parseErr.Line
+		}
+
+		var stackErr *UnknownStackError
+		if errors.As(err, &stackErr) {
+			entry.Kind = "unknown_stack"
+			entry.Stack = stackErr.Stack
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
@@ -1,7 +1,10 @@
 package common
 
 import (
+	"fmt"
+	"math"
 	"slices"
+	"strings"
 )
 
 type NodeAttribute int
@@ -16,11 +19,67 @@ const (
 	IsIsolated // node with no incoming edges nor outgoing edges
 	IsSource   // node with no incoming edges (source)
 	IsSink     // node with no outgoing edges (sink)
+	IsEntry    // card the game starts from (game profile)
+	IsGoal     // card ending the game (game profile)
+	PushesCard // card whose script pushes it (`push card`), to come back with `pop card`
+	PopsCard   // card whose script returns to the last pushed card (`pop card`)
+
+	IsUnreachable // card unreachable from the entry card
+	IsSoftLock    // card reachable from the entry card, from which no goal card can be reached
+	IsCutOnly     // card reachable from the entry card only through disabled links or virtual cards
 )
 
+var nodeAttributeNames = map[NodeAttribute]string{
+	IsCard:            "IsCard",
+	IsStack:           "IsStack",
+	IsVirtual:         "IsVirtual",
+	ContainsBluePage:  "ContainsBluePage",
+	ContainsRedPage:   "ContainsRedPage",
+	ContainsWhitePage: "ContainsWhitePage",
+	IsIsolated:        "IsIsolated",
+	IsSource:          "IsSource",
+	IsSink:            "IsSink",
+	IsEntry:           "IsEntry",
+	IsGoal:            "IsGoal",
+	PushesCard:        "PushesCard",
+	PopsCard:          "PopsCard",
+	IsUnreachable:     "IsUnreachable",
+	IsSoftLock:        "IsSoftLock",
+	IsCutOnly:         "IsCutOnly",
+}
+
+func (a NodeAttribute) String() string {
+	if name, ok := nodeAttributeNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("NodeAttribute(%d)", int(a))
+}
+
+// NodeAttributes returns all the node attributes, in declaration order
+func NodeAttributes() []NodeAttribute {
+	attributes := make([]NodeAttribute, 0, len(nodeAttributeNames))
+	for attr := range nodeAttributeNames {
+		attributes = append(attributes, attr)
+	}
+	slices.Sort(attributes)
+	return attributes
+}
+
+// ParseNodeAttribute returns the node attribute with the given name
+func ParseNodeAttribute(name string) (NodeAttribute, bool) {
+	for attr, attrName := range nodeAttributeNames {
+		if strings.EqualFold(attrName, name) {
+			return attr, true
+		}
+	}
+	return 0, false
+}
+
 type Node struct {
 	Attributes    []NodeAttribute
+	Tags          []string // custom tags produced by the rules file
 	GraphID       int64
+	CardID        int // HyperCard card ID (0 for stacks and for virtual cards referenced by name)
 	StackName     string
 	Name          string
 	OriginalName  *string
@@ -31,6 +90,10 @@ func (n Node) IsOfType(t NodeAttribute) bool {
 	return slices.Contains(n.Attributes, t)
 }
 
+func (n Node) HasTag(tag string) bool {
+	return slices.Contains(n.Tags, tag)
+}
+
 type EdgeAttribute int
 
 const (
@@ -42,10 +105,53 @@ const (
 	Backtracking
 	RestrictiveTransitivityTail
 	RestrictiveTransitivityHead
+	Automatic        // link followed without any player action (e.g., in an `openCard` handler)
+	TransitionEffect // link preceded by a `visual effect` (e.g., a dissolve)
 )
 
+var edgeAttributeNames = map[EdgeAttribute]string{
+	IntraAge:                    "IntraAge",
+	CrossAge:                    "CrossAge",
+	Disabled:                    "Disabled",
+	SelfReference:               "SelfReference",
+	NotImplemented:              "NotImplemented",
+	Backtracking:                "Backtracking",
+	RestrictiveTransitivityTail: "RestrictiveTransitivityTail",
+	RestrictiveTransitivityHead: "RestrictiveTransitivityHead",
+	Automatic:                   "Automatic",
+	TransitionEffect:            "TransitionEffect",
+}
+
+func (a EdgeAttribute) String() string {
+	if name, ok := edgeAttributeNames[a]; ok {
+		return name
+	}
+	return fmt.Sprintf("EdgeAttribute(%d)", int(a))
+}
+
+// EdgeAttributes returns all the edge attributes, in declaration order
+func EdgeAttributes() []EdgeAttribute {
+	attributes := make([]EdgeAttribute, 0, len(edgeAttributeNames))
+	for attr := range edgeAttributeNames {
+		attributes = append(attributes, attr)
+	}
+	slices.Sort(attributes)
+	return attributes
+}
+
+// ParseEdgeAttribute returns the edge attribute with the given name
+func ParseEdgeAttribute(name string) (EdgeAttribute, bool) {
+	for attr, attrName := range edgeAttributeNames {
+		if strings.EqualFold(attrName, name) {
+			return attr, true
+		}
+	}
+	return 0, false
+}
+
 type Edge struct {
 	Attributes     []EdgeAttribute
+	Tags           []string // custom tags produced by the rules file
 	Source, Target *Node
 	TransitivityID int64
 }
@@ -54,6 +160,10 @@ func (e Edge) IsOfType(t EdgeAttribute) bool {
 	return slices.Contains(e.Attributes, t)
 }
 
+func (e Edge) HasTag(tag string) bool {
+	return slices.Contains(e.Tags, tag)
+}
+
 func (e Edge) GetSourceAndTargetIDs() []int64 {
 	return []int64{e.Source.GraphID, e.Target.GraphID}
 }
@@ -68,14 +178,82 @@ type Metadata struct {
 	Stats       GraphStats
 }
 
+// Clone returns a deep copy of the nodes and edges (the edges referencing the copied nodes),
+// without the statistics
+func (m *Metadata) Clone() *Metadata {
+	clone := &Metadata{
+		TotalCards:  m.TotalCards,
+		TotalStacks: m.TotalStacks,
+		TotalNodes:  m.TotalNodes,
+		TotalEdges:  m.TotalEdges,
+		Nodes:       make([]*Node, 0, len(m.Nodes)),
+		Edges:       make([]*Edge, 0, len(m.Edges)),
+	}
+
+	nodes := make(map[*Node]*Node, len(m.Nodes))
+	for _, node := range m.Nodes {
+		copied := *node
+		copied.Attributes = slices.Clone(node.Attributes)
+		copied.Tags = slices.Clone(node.Tags)
+		nodes[node] = &copied
+		clone.Nodes = append(clone.Nodes, &copied)
+	}
+
+	for _, edge := range m.Edges {
+		copied := *edge
+		copied.Attributes = slices.Clone(edge.Attributes)
+		copied.Tags = slices.Clone(edge.Tags)
+		copied.Source = nodes[edge.Source]
+		copied.Target = nodes[edge.Target]
+		clone.Edges = append(clone.Edges, &copied)
+	}
+
+	return clone
+}
+
 // GraphStats contains analysis results for a graph
 type GraphStats struct {
 	// path and connectivity analysis
-	ShortestPaths          map[int64]map[int64]ShortestPathInfo
+	ShortestPaths          *DistanceMatrix
 	MostSeparatedNodes     NodePairInfo
+	GoalPaths              []ShortestPathInfo // from the entry node to each goal node
 	ConnectedComponents    [][]int64
 	DisconnectedComponents [][]NodeInfo
 
+	// strongly connected components and their condensation (DAG)
+	StronglyConnectedComponents []SCCInfo
+	CondensationEdges           []CondensationEdge
+
+	// robustness (ignoring the edge directions for the bridges and articulation points)
+	Bridges            []EdgeGroupInfo
+	ArticulationPoints []NodeInfo
+	StackConnectivity  []StackConnectivityInfo
+
+	// communities (modularity) compared with the stacks
+	Communities CommunityStats
+
+	// elementary cycles (bounded length), and whether their enumeration stopped at the limit
+	Cycles          []CycleInfo
+	CyclesTruncated bool
+
+	// physical positions (viewpoints) reconstructed from the rotation cycles
+	Positions PositionStats
+
+	// Age-level quotient graph (cards collapsed by stack)
+	AgeQuotient AgeQuotient
+
+	// push card / pop card discipline
+	Pushdown PushdownStats
+
+	// reachability from the entry card (unreachable cards and soft-locks)
+	Reachability ReachabilityStats
+
+	// centrality (per node)
+	Centrality CentralityStats
+
+	// global metrics of the graph and of each stack
+	Metrics MetricsReport
+
 	// node degree information
 	MostIncomingNode    NodeDegreeInfo
 	MostOutgoingNode    NodeDegreeInfo
@@ -101,6 +279,222 @@ type ShortestPathInfo struct {
 	Path     []int64
 }
 
+// DistanceMatrix contains the shortest paths between all pairs of nodes, stored as compact
+// row-major matrices (one row per node, one column per state of the state graph): the distances,
+// and the predecessors from which the paths are rebuilt on demand
+// NOTE: the first states are the nodes themselves (in the order of Nodes), the other ones
+// the transitive cards passed through
+type DistanceMatrix struct {
+	Nodes        []int64   // node IDs, sorted
+	States       []int64   // node ID of each state
+	Distances    []float64 // +Inf if unreachable
+	Predecessors []int32   // previous state on the shortest path (-1 if none)
+}
+
+// index returns the row (and column) of a node
+func (m *DistanceMatrix) index(id int64) (int, bool) {
+	if m == nil {
+		return -1, false
+	}
+	return slices.BinarySearch(m.Nodes, id)
+}
+
+// Distance returns the length of the shortest path between two nodes (+Inf if unreachable)
+func (m *DistanceMatrix) Distance(from, to int64) float64 {
+	row, fromExists := m.index(from)
+	column, toExists := m.index(to)
+	if !fromExists || !toExists {
+		return math.Inf(1)
+	}
+	return m.Distances[row*len(m.States)+column]
+}
+
+// ShortestPath returns the shortest path between two distinct nodes, if any
+func (m *DistanceMatrix) ShortestPath(from, to int64) (ShortestPathInfo, bool) {
+	row, fromExists := m.index(from)
+	column, toExists := m.index(to)
+	if !fromExists || !toExists || row == column {
+		return ShortestPathInfo{}, false
+	}
+
+	offset := row * len(m.States)
+	distance := m.Distances[offset+column]
+	if math.IsInf(distance, 1) {
+		return ShortestPathInfo{}, false
+	}
+
+	path := []int64{m.States[column]}
+	for state := int32(column); int(state) != row; {
+		state = m.Predecessors[offset+int(state)]
+		path = append(path, m.States[state])
+	}
+	slices.Reverse(path)
+
+	return ShortestPathInfo{From: from, To: to, Distance: distance, Path: path}, true
+}
+
+// SCCInfo describes a strongly connected component (SCC): a region of cards
+// that can all be reached from one another
+type SCCInfo struct {
+	ID     int      // index in GraphStats.StronglyConnectedComponents
+	Nodes  []int64  // sorted node IDs
+	Stacks []string // sorted names of the stacks spanned by the component
+	IsTrap bool     // reachable from another component, but no edge leaves it (one-way region)
+}
+
+// CondensationEdge is an edge of the condensation DAG, between two SCCs
+type CondensationEdge struct {
+	From  int // SCC ID
+	To    int // SCC ID
+	Count int // number of links between the two components (disabled links excepted)
+}
+
+// CentralityMetric identifies a node centrality metric
+type CentralityMetric string
+
+const (
+	InDegreeCentrality    CentralityMetric = "indegree"
+	OutDegreeCentrality   CentralityMetric = "outdegree"
+	BetweennessCentrality CentralityMetric = "betweenness" // cards acting as corridors
+	PageRankCentrality    CentralityMetric = "pagerank"
+	ClosenessCentrality   CentralityMetric = "closeness" // harmonic closeness (robust to unreachable nodes)
+	EigenvectorCentrality CentralityMetric = "eigenvector"
+)
+
+// CentralityMetrics lists the centrality metrics in display order
+var CentralityMetrics = []CentralityMetric{
+	InDegreeCentrality,
+	OutDegreeCentrality,
+	BetweennessCentrality,
+	PageRankCentrality,
+	ClosenessCentrality,
+	EigenvectorCentrality,
+}
+
+// ParseCentralityMetric returns the centrality metric with the given name
+func ParseCentralityMetric(name string) (CentralityMetric, bool) {
+	for _, metric := range CentralityMetrics {
+		if strings.EqualFold(string(metric), name) {
+			return metric, true
+		}
+	}
+	return "", false
+}
+
+// NodeCentrality contains the centrality metrics of a node
+// (betweenness and closeness are normalized to [0, 1])
+type NodeCentrality struct {
+	ID          int64
+	Name        string
+	InDegree    int
+	OutDegree   int
+	Betweenness float64
+	PageRank    float64
+	Closeness   float64
+	Eigenvector float64
+}
+
+// Value returns the value of the given metric
+func (c NodeCentrality) Value(metric CentralityMetric) float64 {
+	switch metric {
+	case InDegreeCentrality:
+		return float64(c.InDegree)
+	case OutDegreeCentrality:
+		return float64(c.OutDegree)
+	case BetweennessCentrality:
+		return c.Betweenness
+	case PageRankCentrality:
+		return c.PageRank
+	case ClosenessCentrality:
+		return c.Closeness
+	case EigenvectorCentrality:
+		return c.Eigenvector
+	default:
+		return 0
+	}
+}
+
+// CentralityStats contains the centrality of every node and the degree distributions
+type CentralityStats struct {
+	Nodes                 map[int64]NodeCentrality
+	InDegreeDistribution  map[int]int // degree -> number of nodes
+	OutDegreeDistribution map[int]int // degree -> number of nodes
+}
+
+// Ranked returns the nodes sorted by decreasing value of the metric (ties broken by ID),
+// limited to the first n nodes (all nodes if n <= 0)
+func (s CentralityStats) Ranked(metric CentralityMetric, n int) []NodeCentrality {
+	ranked := make([]NodeCentrality, 0, len(s.Nodes))
+	for _, c := range s.Nodes {
+		ranked = append(ranked, c)
+	}
+
+	slices.SortFunc(ranked, func(a, b NodeCentrality) int {
+		if va, vb := a.Value(metric), b.Value(metric); va != vb {
+			if va > vb {
+				return -1
+			}
+			return 1
+		}
+		if a.ID < b.ID {
+			return -1
+		}
+		if a.ID > b.ID {
+			return 1
+		}
+		return 0
+	})
+
+	if n > 0 && len(ranked) > n {
+		ranked = ranked[:n]
+	}
+	return ranked
+}
+
+// MetricsReport contains the global metrics of the graph and of each stack (sorted by name)
+type MetricsReport struct {
+	Graph  GraphMetrics
+	Stacks []GraphMetrics
+}
+
+// GraphMetrics contains the global metrics of the graph, or of a stack
+// NOTE: the structural metrics (density to degrees) are computed on the traversable edges
+// between the nodes of the scope (see the centrality), and the eccentricities on the shortest
+// paths within the scope, between the nodes of its largest strongly connected component
+type GraphMetrics struct {
+	Stack             string // empty for the whole graph
+	Nodes             int
+	Edges             int     // linked pairs of nodes
+	Density           float64 // edges / possible edges
+	Reciprocity       float64 // share of the edges whose reverse edge exists
+	AverageClustering float64 // average local clustering coefficient (edge directions ignored)
+	Assortativity     float64 // out-degree/in-degree assortativity (0 if undefined)
+
+	LargestComponent         int     // nodes of the largest strongly connected component (see the eccentricities)
+	Diameter                 float64 // greatest eccentricity
+	Radius                   float64 // lowest eccentricity
+	EccentricityDistribution []HistogramBin
+	Center                   []NodeInfo // nodes whose eccentricity is the radius
+	Periphery                []NodeInfo // nodes whose eccentricity is the diameter
+
+	InDegreeDistribution  []HistogramBin
+	OutDegreeDistribution []HistogramBin
+	NodeAttributes        []AttributeCount // every node attribute (see NodeAttributes)
+	EdgeAttributes        []AttributeCount // every edge attribute, on the links from the nodes (disabled ones included)
+}
+
+// HistogramBin is the number of nodes with a given value
+type HistogramBin struct {
+	Value float64
+	Count int
+}
+
+// AttributeCount is the number of nodes or links with a given attribute
+type AttributeCount struct {
+	Attribute string
+	Count     int
+}
+
 // NodeDegreeInfo contains the degree information for a node
 type NodeDegreeInfo struct {
 	ID     int64
@@ -118,6 +512,162 @@ type EdgeInfo struct {
 	To   NodeInfo
 }
 
+// CommunityStats contains the communities detected by modularity optimization (Louvain),
+// and their comparison with the stacks
+type CommunityStats struct {
+	Communities   [][]int64     // sorted node IDs; the index is the community ID (largest first)
+	NodeCommunity map[int64]int // node ID -> community ID
+	Modularity    float64
+	// NMI is the normalized mutual information between the communities and the stacks
+	// (1: identical partitions; 0: independent partitions)
+	NMI float64
+	// ConfusionMatrix counts the nodes of each stack (row, see Stacks) in each community (column)
+	Stacks          []string
+	ConfusionMatrix [][]int
+	// Disagreements lists the nodes whose stack is not the main stack of their community
+	Disagreements []CommunityDisagreement
+}
+
+// CommunityDisagreement is a node whose stack is not the main stack of its community
+type CommunityDisagreement struct {
+	Node               NodeInfo
+	Stack              string
+	Community          int
+	CommunityMainStack string
+}
+
+// CycleKind classifies the elementary cycles
+type CycleKind string
+
+const (
+	RotationLoop    CycleKind = "rotation"    // 4 cards of a stack, turning in place (in both directions)
+	CorridorLoop    CycleKind = "corridor"    // short cycle of a stack returning to its start
+	ExplorationLoop CycleKind = "exploration" // long cycle, or cycle spanning several stacks
+)
+
+// CycleInfo describes an elementary cycle
+type CycleInfo struct {
+	Kind           CycleKind
+	Nodes          []int64         // starting with the lowest ID; the last node links back to the first
+	Stacks         []string        // sorted
+	EdgeAttributes []EdgeAttribute // attributes found along the cycle, sorted
+}
+
+// PositionInfo is a physical position: the cards showing the different facing directions
+// from the same spot
+type PositionInfo struct {
+	ID       int
+	Stack    string
+	Cards    []int64  // sorted node IDs
+	Evidence []string // why the cards were grouped (empty for single cards)
+}
+
+// PositionEdge links two positions (the player can walk from a position to another)
+type PositionEdge struct {
+	From  int // position ID
+	To    int // position ID
+	Count int // number of card edges
+}
+
+// PositionStats contains the position-level graph
+type PositionStats struct {
+	Positions    []PositionInfo
+	NodePosition map[int64]int // node ID -> position ID
+	Edges        []PositionEdge
+}
+
+// AgeLink is an edge of the Age-level quotient graph: the links from a stack to another
+type AgeLink struct {
+	From            string
+	To              string
+	Count           int            // number of links (including the disabled ones)
+	AttributeCounts map[string]int // edge attribute -> number of links
+	ExitCards       []NodeInfo     // nodes of `From` linking to `To`
+	EntryCards      []NodeInfo     // nodes of `To` linked from `From`
+}
+
+// AgeQuotient is the Age-level quotient graph: the cards collapsed by stack
+type AgeQuotient struct {
+	Stacks []string       // sorted
+	Cards  map[string]int // stack -> number of nodes
+	Links  []AgeLink
+}
+
+// Matrix returns the cross-age transition matrix: the number of links from
+// each stack (row) to each stack (column), in the order of `Stacks`
+func (q AgeQuotient) Matrix() [][]int {
+	index := make(map[string]int, len(q.Stacks))
+	for i, stack := range q.Stacks {
+		index[stack] = i
+	}
+
+	matrix := make([][]int, len(q.Stacks))
+	for i := range matrix {
+		matrix[i] = make([]int, len(q.Stacks))
+	}
+
+	for _, link := range q.Links {
+		matrix[index[link.From]][index[link.To]] = link.Count
+	}
+
+	return matrix
+}
+
+// PushdownSummary summarizes the excursions starting from a card pushing itself:
+// the cards reachable before returning to it (without any nested push),
+// and the cards returning to it (`pop card`)
+type PushdownSummary struct {
+	Pusher    NodeInfo
+	Excursion []NodeInfo
+	Poppers   []NodeInfo
+}
+
+// PushdownStats contains the push/pop summaries and the reachability from the entry card
+// under the push card / pop card discipline
+type PushdownStats struct {
+	Summaries []PushdownSummary
+	// number of nodes reachable from the entry card
+	Reachable int
+	// nodes reachable from the entry card only through the links the cost model
+	// cannot traverse (e.g., the links from a pushed card to a popping card)
+	ReachableThroughPush []NodeInfo
+}
+
+// ReachabilityInfo is a card found by the reachability analysis, with a witness path
+// from the entry card (if reachable) or the explanation of its status
+type ReachabilityInfo struct {
+	Node        NodeInfo
+	Path        []int64
+	Explanation string
+}
+
+// ReachabilityStats contains the global reachability of the cards from the entry card
+type ReachabilityStats struct {
+	Entry NodeInfo
+	// cards unreachable from the entry card, even through the disabled links
+	Unreachable []ReachabilityInfo
+	// reachable cards from which no goal card can be reached (potential soft-locks)
+	SoftLocks []ReachabilityInfo
+	// cards reachable only through disabled links or virtual cards
+	CutOnly []ReachabilityInfo
+}
+
+// EdgeGroupInfo describes the (parallel) edges between two nodes
+type EdgeGroupInfo struct {
+	From  NodeInfo
+	To    NodeInfo
+	Count int // number of edges
+}
+
+// StackConnectivityInfo contains the edge connectivity from a stack to another:
+// the maximum number of edge-disjoint paths, which equals the size of the minimum edge cut
+type StackConnectivityInfo struct {
+	From             string
+	To               string
+	EdgeConnectivity int
+	MinCut           []EdgeGroupInfo
+}
+
 type TransitivityRank int
 
 const (
@@ -0,0 +1,13 @@
+package config
+
+// CostModel configures the traversal cost of the edges used by the path analyses
+// (see graph.NewCostModel)
+type CostModel struct {
+	// Preset is the base cost model: `clicks` (default), `age-switches`, or `backtracking=N`
+	Preset string `json:"preset,omitempty"`
+	// extra costs (non-negative) of the edges with the given attributes (e.g., `CrossAge`,
+	// `Automatic`, or `TransitionEffect`), custom tags, or source stacks, added to the preset cost
+	Attributes map[string]float64 `json:"attributes,omitempty"`
+	Tags       map[string]float64 `json:"tags,omitempty"`
+	Stacks     map[string]float64 `json:"stacks,omitempty"`
+}
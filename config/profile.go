@@ -0,0 +1,157 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Profile bundles the game-specific constants of a HyperCard title
+// (Myst, The Manhole, Cosmic Osmo, fan stacks...)
+type Profile struct {
+	Name string `json:"name"`
+	// EntryNode is the card the game starts from
+	EntryNode *CardRef `json:"entryNode,omitempty"`
+	// GoalNodes are the cards ending the game
+	GoalNodes []CardRef `json:"goalNodes,omitempty"`
+	// Manifest describes the expected corpus
+	Manifest Manifest `json:"manifest"`
+	// Rules tag the nodes and edges (e.g., the Myst pages)
+	Rules *Rules `json:"rules,omitempty"`
+	// StackDisplayNames maps the stack names to human-readable names (e.g., the Ages)
+	StackDisplayNames map[string]string `json:"stackDisplayNames,omitempty"`
+	// CostModel sets the traversal cost of the edges (one click per edge by default)
+	CostModel *CostModel `json:"costModel,omitempty"`
+}
+
+// CardRef references a card by its stack name and HyperCard ID
+type CardRef struct {
+	Stack string `json:"stack"`
+	ID    int    `json:"id"`
+}
+
+// Manifest describes the expected corpus (zero values disable the checks)
+// NOTE: to replace with a more robust mechanism, like hashing the files
+type Manifest struct {
+	Stacks uint `json:"stacks,omitempty"`
+	Cards  uint `json:"cards,omitempty"`
+}
+
+const (
+	MystProfileName    = "myst"
+	GenericProfileName = "generic"
+)
+
+// MystProfile returns the profile of the 1993 Myst CD-ROM (Macintosh)
+func MystProfile() *Profile {
+	return &Profile{
+		Name:      MystProfileName,
+		EntryNode: &CardRef{Stack: "Myst", ID: 8336},
+		GoalNodes: []CardRef{
+			{Stack: "Dunny Age", ID: 11088},
+		},
+		Manifest: Manifest{
+			Stacks: 6,
+			Cards:  1355,
+		},
+		Rules: DefaultRules(),
+		StackDisplayNames: map[string]string{
+			"Myst":            "Myst Island",
+			"Channelwood Age": "Channelwood",
+			"Mechanical Age":  "Mechanical",
+			"Selenitic Age":   "Selenitic",
+			"Stoneship Age":   "Stoneship",
+			"Dunny Age":       "D'ni",
+		},
+	}
+}
+
+// GenericProfile returns a profile without any game-specific constant,
+// suitable for exploring an unknown HyperCard title
+func GenericProfile() *Profile {
+	return &Profile{
+		Name:  GenericProfileName,
+		Rules: &Rules{Styles: make(map[string]Style)},
+	}
+}
+
+// LoadProfile returns the built-in profile with the given name, or reads a JSON profile file
+func LoadProfile(nameOrPath string) (*Profile, error) {
+	switch strings.ToLower(nameOrPath) {
+	case "", MystProfileName:
+		return MystProfile(), nil
+	case GenericProfileName:
+		return GenericProfile(), nil
+	}
+
+	content, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		return nil, fmt.Errorf("unknown profile %s: %w", nameOrPath, err)
+	}
+
+	var fileProfile Profile
+	if err := json.Unmarshal(content, &fileProfile); err != nil {
+		return nil, fmt.Errorf("invalid profile file %s: %w", nameOrPath, err)
+	}
+
+	// the default rules (the Myst pages) are specific to the Myst profile
+	rules := &Rules{Styles: make(map[string]Style)}
+	if err := rules.Extend(fileProfile.Rules); err != nil {
+		return nil, fmt.Errorf("invalid profile file %s: %w", nameOrPath, err)
+	}
+	fileProfile.Rules = rules
+
+	if fileProfile.Name == "" {
+		fileProfile.Name = nameOrPath
+	}
+
+	return &fileProfile, nil
+}
+
+// StackDisplayName returns the human-readable name of a stack
+func (p *Profile) StackDisplayName(stack string) string {
+	if name, ok := p.StackDisplayNames[stack]; ok {
+		return name
+	}
+	return stack
+}
+
+// Check verifies that the parsed corpus matches the manifest
+func (m Manifest) Check(totalStacks, totalCards uint) error {
+	if m.Stacks != 0 && totalStacks != m.Stacks {
+		return fmt.Errorf("expected %d stacks, got %d", m.Stacks, totalStacks)
+	}
+
+	if m.Cards != 0 && totalCards != m.Cards {
+		return fmt.Errorf("expected %d cards, got %d", m.Cards, totalCards)
+	}
+
+	return nil
+}
+
+// NodeName returns the name of the node representing the card in the graph
+func (c CardRef) NodeName() string {
+	return fmt.Sprintf("%s:%d", c.Stack, c.ID)
+}
+
+func (c CardRef) String() string {
+	return c.NodeName()
+}
+
+// ParseCardRef parses a card reference formatted as `{stack name}:{card id}`
+// (e.g., `Myst:8336`)
+func ParseCardRef(s string) (CardRef, error) {
+	i := strings.LastIndex(s, ":")
+	if i <= 0 || i == len(s)-1 {
+		return CardRef{}, fmt.Errorf("invalid card reference %q (expected {stack}:{card id})", s)
+	}
+
+	id, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return CardRef{}, fmt.Errorf("invalid card ID in %q: %w", s, err)
+	}
+
+	return CardRef{Stack: s[:i], ID: id}, nil
+}
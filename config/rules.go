@@ -0,0 +1,154 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// Rules declares the script patterns that tag nodes (cards) and edges (links),
+// and the style the renderer uses for each tag
+//
+// A tag named after a built-in node attribute (e.g., `ContainsBluePage`) also sets
+// that attribute on the node; any other tag is kept as a custom tag
+type Rules struct {
+	// ReplaceDefaults discards the default rules instead of extending them
+	ReplaceDefaults bool             `json:"replaceDefaults,omitempty"`
+	NodeRules       []Rule           `json:"nodeRules,omitempty"`
+	EdgeRules       []Rule           `json:"edgeRules,omitempty"`
+	Styles          map[string]Style `json:"styles,omitempty"`
+}
+
+// Rule associates a script pattern (regular expression) with a tag
+type Rule struct {
+	Name    string `json:"name,omitempty"`
+	Pattern string `json:"pattern"`
+	Tag     string `json:"tag"`
+
+	re *regexp.Regexp
+}
+
+// Style describes how the renderer draws a tagged node or edge
+// (empty fields keep the default style)
+type Style struct {
+	FillColor string  `json:"fillColor,omitempty"` // nodes only
+	Color     string  `json:"color,omitempty"`     // node border or edge color
+	FontColor string  `json:"fontColor,omitempty"` // nodes only
+	Shape     string  `json:"shape,omitempty"`     // nodes only
+	Style     string  `json:"style,omitempty"`     // e.g., "dashed"
+	PenWidth  float64 `json:"penWidth,omitempty"`
+}
+
+// DefaultRules returns the rules detecting the Myst pages
+func DefaultRules() *Rules {
+	rules := &Rules{
+		NodeRules: []Rule{
+			{Name: "blue page", Pattern: `(?i)put "(\d+),A,0" into ALL_Page`, Tag: "ContainsBluePage"},
+			{Name: "red page", Pattern: `(?i)put "(\d+),S,0" into ALL_Page`, Tag: "ContainsRedPage"},
+			{Name: "white page", Pattern: `(?i)put "Atrus" into ALL_Page`, Tag: "ContainsWhitePage"},
+		},
+		Styles: make(map[string]Style),
+	}
+
+	// the default patterns are known to be valid
+	_ = rules.compile()
+
+	return rules
+}
+
+// ReadRules reads a JSON rules file as is, without the default rules
+func ReadRules(path string) (*Rules, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules Rules
+	if err := json.Unmarshal(content, &rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+
+	if err := rules.compile(); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+
+	return &rules, nil
+}
+
+// Extend appends the rules and styles of other to r (the styles of other take precedence);
+// the node rules of r are discarded first if other replaces the defaults
+func (r *Rules) Extend(other *Rules) error {
+	if other == nil {
+		return nil
+	}
+
+	if other.ReplaceDefaults {
+		r.NodeRules = nil
+	}
+
+	r.NodeRules = append(r.NodeRules, other.NodeRules...)
+	r.EdgeRules = append(r.EdgeRules, other.EdgeRules...)
+
+	if r.Styles == nil {
+		r.Styles = make(map[string]Style, len(other.Styles))
+	}
+	for tag, style := range other.Styles {
+		r.Styles[tag] = style
+	}
+
+	return r.compile()
+}
+
+// compile compiles the patterns of all the rules
+func (r *Rules) compile() error {
+	compileAll := func(rules []Rule) error {
+		for i := range rules {
+			if rules[i].re != nil {
+				continue
+			}
+
+			if rules[i].Tag == "" {
+				return fmt.Errorf("rule %q has no tag", rules[i].Pattern)
+			}
+
+			re, err := regexp.Compile(rules[i].Pattern)
+			if err != nil {
+				return fmt.Errorf("rule %q: %w", rules[i].Pattern, err)
+			}
+			rules[i].re = re
+		}
+		return nil
+	}
+
+	if err := compileAll(r.NodeRules); err != nil {
+		return err
+	}
+	return compileAll(r.EdgeRules)
+}
+
+// Match reports whether the script line matches the rule pattern
+// (a rule that has not been compiled never matches)
+func (r Rule) Match(line string) bool {
+	return r.re != nil && r.re.MatchString(line)
+}
+
+// NodeTags returns the tags of the node rules matched by the script line
+func (r *Rules) NodeTags(line string) []string {
+	return matchingTags(r.NodeRules, line)
+}
+
+// EdgeTags returns the tags of the edge rules matched by the script line
+func (r *Rules) EdgeTags(line string) []string {
+	return matchingTags(r.EdgeRules, line)
+}
+
+func matchingTags(rules []Rule, line string) []string {
+	var tags []string
+	for _, rule := range rules {
+		if rule.Match(line) {
+			tags = append(tags, rule.Tag)
+		}
+	}
+	return tags
+}
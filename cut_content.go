@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
+	"github.com/glthr/DeMystify/graph"
+	"github.com/glthr/DeMystify/renderer/dot"
+)
+
+// cutContentStyle highlights the links and cards restored by the cut-content mode
+var cutContentStyle = config.Style{Color: "#D81B60", FillColor: "#F8BBD0", PenWidth: 2}
+
+// RunCutContent analyzes the graph again with the disabled links enabled and the virtual cards
+// treated as real ones, then prints and saves the comparison with the original graph
+func RunCutContent(loaded *loadedGraph, cycleOptions graph.CycleOptions, dotConfig dot.Config, render bool) {
+	fmt.Println("Analyzing the cut content...")
+
+	metadata := graph.CutContentMetadata(loaded.metadata)
+
+	g, err := graph.NewGraph(metadata)
+	if err != nil {
+		log.Fatalf("error while instantiating the cut-content graph: %v", err)
+	}
+
+	g.CycleOptions = cycleOptions
+	g.SetCostModel(loaded.costModel)
+	g.Process(loaded.profile)
+
+	report := graph.CompareCutContent(loaded.graph, g)
+	PrintCutContent(report)
+
+	if err := WriteCutContentReport(report, cutContentReportPath); err != nil {
+		log.Printf("unable to save the cut-content report: %v", err)
+	}
+
+	if render {
+		fmt.Println("Generating the cut-content graph...")
+
+		cutContentConfig := dotConfig
+		cutContentConfig.TagStyles = make(map[string]config.Style, len(dotConfig.TagStyles)+1)
+		for tag, style := range dotConfig.TagStyles {
+			cutContentConfig.TagStyles[tag] = style
+		}
+		cutContentConfig.TagStyles[graph.CutContentTag] = cutContentStyle
+		// NOTE: the overlays refer to the cycles of the original graph
+		cutContentConfig.Overlays = nil
+
+		RenderAlternateGraph(g, metadata, cutContentConfig, cutContentPath)
+	}
+}
+
+// PrintCutContent prints the comparison between the original graph and the cut-content graph
+func PrintCutContent(report graph.CutContentReport) {
+	fmt.Printf("Cut content: %d disabled link(s) enabled, %d virtual card(s) made real (see %s)\n",
+		len(report.EnabledEdges), len(report.RealizedNodes), cutContentReportPath)
+	for _, edge := range report.EnabledEdges {
+		fmt.Printf("  %s -> %s\n", edge.From.Name, edge.To.Name)
+	}
+
+	fmt.Printf("  newly reachable cards from the entry card: %d\n", len(report.NewlyReachable))
+	for _, node := range report.NewlyReachable {
+		fmt.Printf("    %s\n", node.Name)
+	}
+
+	fmt.Printf("  connected components: %d -> %d\n",
+		report.OriginalConnectedComponents, report.CutContentConnectedComponents)
+	fmt.Printf("  strongly connected components: %d -> %d (traps: %d -> %d)\n",
+		report.OriginalSCCs, report.CutContentSCCs, report.OriginalTraps, report.CutContentTraps)
+	fmt.Printf("  most separated nodes: %s\n", formatNodePair(report.OriginalMostSeparatedNodes))
+	fmt.Printf("                     -> %s\n", formatNodePair(report.CutContentMostSeparatedNodes))
+}
+
+// formatNodePair formats a pair of nodes and their distance
+func formatNodePair(pair common.NodePairInfo) string {
+	return fmt.Sprintf("%s to %s (%.4g)", pair.Source.Name, pair.Target.Name, pair.Distance)
+}
+
+// WriteCutContentReport saves the cut-content report as JSON
+func WriteCutContentReport(report graph.CutContentReport, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return report.WriteJSON(file)
+}
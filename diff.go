@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/glthr/DeMystify/diff"
+	"github.com/glthr/DeMystify/graph"
+	pdf "github.com/glthr/DeMystify/renderer"
+)
+
+// runDiff compares the Myst Graphs of two corpora (e.g., two CD pressings)
+func runDiff(args []string) {
+	flags := flag.NewFlagSet(diffCommand, flag.ExitOnError)
+	graphOptions := registerGraphFlags(flags)
+	render := flags.Bool("render", false, "render the differences in "+diffDOTPath)
+	changedOnly := flags.Bool("changed-only", false, "render only the changed nodes and edges")
+	_ = flags.Parse(args)
+
+	if flags.NArg() < 2 {
+		log.Fatal("Usage: go run . diff [-profile <name|profile.json>] [-rules <rules.json>] [-continue-on-error] [-cost-model <preset>] [-no-cache] [-render] [-changed-only] <old_directory_path> <new_directory_path>")
+	}
+
+	if *render {
+		if err := CheckNeatoInstalled(); err != nil {
+			log.Fatalf("Neato is not installed: %v", err)
+		}
+	}
+
+	// NOTE: both corpora are analyzed with the same profile and rules, but their integrity
+	// and error reports are saved apart
+	oldOptions, newOptions := graphOptions, graphOptions
+	oldOptions.reportSuffix, newOptions.reportSuffix = "old", "new"
+	oldGraph := loadGraph(flags.Arg(0), oldOptions, graph.DefaultCycleOptions())
+	newGraph := loadGraph(flags.Arg(1), newOptions, graph.DefaultCycleOptions())
+
+	report := diff.Compare(oldGraph.metadata, newGraph.metadata)
+	fmt.Printf("Differences: %s (see %s)\n", report.Summary(), diffTextPath)
+
+	var text bytes.Buffer
+	_ = report.WriteText(&text)
+	if err := os.WriteFile(diffTextPath, text.Bytes(), 0644); err != nil {
+		log.Fatalf("unable to save the diff report: %v", err)
+	}
+
+	if *render {
+		fmt.Println("Generating the diff rendering...")
+
+		var content bytes.Buffer
+		_ = report.WriteDOT(&content, *changedOnly)
+		if err := os.WriteFile(diffDOTPath, content.Bytes(), 0644); err != nil {
+			log.Fatalf("unable to save the diff rendering: %v", err)
+		}
+
+		pdf.RenderPDF(diffDOTPath)
+	}
+
+	if oldGraph.parseErrors.HasErrors() || newGraph.parseErrors.HasErrors() {
+		os.Exit(exitPartialSuccess)
+	}
+}
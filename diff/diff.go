@@ -0,0 +1,290 @@
+package diff
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// Report is the structural difference between two Myst Graph builds (old and new)
+type Report struct {
+	AddedNodes   []*common.Node
+	RemovedNodes []*common.Node
+	ChangedNodes []NodeChange
+
+	AddedEdges   []*common.Edge
+	RemovedEdges []*common.Edge
+	ChangedEdges []EdgeChange
+
+	StatsDeltas []StatDelta
+
+	// all the nodes and edges of both builds (new ones first), for the rendering
+	nodes    map[string]*common.Node
+	edges    map[string][]*common.Edge // edge pair key -> edges
+	oldEdges map[*common.Edge]bool
+	old      *common.Metadata
+	new      *common.Metadata
+}
+
+// NodeChange is a node of both builds whose attributes, tags, or background changed
+type NodeChange struct {
+	Old, New *common.Node
+	Changes  []string
+}
+
+// EdgeChange is a pair of nodes linked in both builds by edges with different attributes
+type EdgeChange struct {
+	Source, Target string // node keys
+	Old, New       []string
+}
+
+// StatDelta compares a statistic of both builds
+type StatDelta struct {
+	Name     string
+	Old, New string
+}
+
+// Changed reports whether the statistic differs
+func (d StatDelta) Changed() bool {
+	return d.Old != d.New
+}
+
+// IsEmpty reports whether both builds are structurally identical
+func (r *Report) IsEmpty() bool {
+	return len(r.AddedNodes) == 0 && len(r.RemovedNodes) == 0 && len(r.ChangedNodes) == 0 &&
+		len(r.AddedEdges) == 0 && len(r.RemovedEdges) == 0 && len(r.ChangedEdges) == 0
+}
+
+// NodeKey identifies a node across builds: its stack and HyperCard card ID
+// (its name for the stacks and for the virtual cards referenced by name)
+// NOTE: the graph IDs are derived from the same information (see graph.StableNodeID)
+func NodeKey(node *common.Node) string {
+	if node.CardID != 0 {
+		return fmt.Sprintf("%s:%d", node.StackName, node.CardID)
+	}
+	return node.Name
+}
+
+// edgePairKey identifies the edges from a node to another across builds
+func edgePairKey(edge *common.Edge) string {
+	return fmt.Sprintf("%s -> %s", NodeKey(edge.Source), NodeKey(edge.Target))
+}
+
+// edgeSignature describes the attributes and tags of an edge
+// NOTE: the transitivity IDs are ignored: they derive from the position of the link among the
+// links of its source card (see parser.transitivityID), so adding or removing another link of
+// the card would report the unchanged links as changed
+func edgeSignature(edge *common.Edge) string {
+	var parts []string
+	for _, attr := range edge.Attributes {
+		parts = append(parts, attr.String())
+	}
+	for _, tag := range edge.Tags {
+		parts = append(parts, "#"+tag)
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "+")
+}
+
+// Compare computes the structural difference between two Myst Graph builds
+// (their statistics are compared if they have been processed, see graph.Process)
+func Compare(oldMetadata, newMetadata *common.Metadata) *Report {
+	r := &Report{
+		nodes:    make(map[string]*common.Node),
+		edges:    make(map[string][]*common.Edge),
+		oldEdges: make(map[*common.Edge]bool, len(oldMetadata.Edges)),
+		old:      oldMetadata,
+		new:      newMetadata,
+	}
+
+	for _, edge := range oldMetadata.Edges {
+		r.oldEdges[edge] = true
+	}
+
+	r.compareNodes()
+	r.compareEdges()
+	r.compareStats()
+
+	return r
+}
+
+// compareNodes matches the nodes by key
+func (r *Report) compareNodes() {
+	oldNodes := indexNodes(r.old)
+	newNodes := indexNodes(r.new)
+
+	for _, key := range sortedKeys(newNodes) {
+		newNode := newNodes[key]
+		r.nodes[key] = newNode
+
+		oldNode, exists := oldNodes[key]
+		if !exists {
+			r.AddedNodes = append(r.AddedNodes, newNode)
+			continue
+		}
+
+		if changes := nodeChanges(oldNode, newNode); len(changes) > 0 {
+			r.ChangedNodes = append(r.ChangedNodes, NodeChange{Old: oldNode, New: newNode, Changes: changes})
+		}
+	}
+
+	for _, key := range sortedKeys(oldNodes) {
+		if _, exists := newNodes[key]; !exists {
+			r.RemovedNodes = append(r.RemovedNodes, oldNodes[key])
+			r.nodes[key] = oldNodes[key]
+		}
+	}
+}
+
+// nodeChanges describes the differences between two versions of a node
+func nodeChanges(oldNode, newNode *common.Node) []string {
+	var changes []string
+
+	describe := func(label string, oldValues, newValues []string) {
+		for _, value := range newValues {
+			if !slices.Contains(oldValues, value) {
+				changes = append(changes, fmt.Sprintf("+%s %s", label, value))
+			}
+		}
+		for _, value := range oldValues {
+			if !slices.Contains(newValues, value) {
+				changes = append(changes, fmt.Sprintf("-%s %s", label, value))
+			}
+		}
+	}
+
+	describe("attribute", nodeAttributeNames(oldNode), nodeAttributeNames(newNode))
+	describe("tag", oldNode.Tags, newNode.Tags)
+
+	oldBackground, newBackground := stringValue(oldNode.SecondaryName), stringValue(newNode.SecondaryName)
+	if oldBackground != newBackground {
+		changes = append(changes, fmt.Sprintf("background %q -> %q", oldBackground, newBackground))
+	}
+
+	oldName, newName := stringValue(oldNode.OriginalName), stringValue(newNode.OriginalName)
+	if oldName != newName {
+		changes = append(changes, fmt.Sprintf("original name %q -> %q", oldName, newName))
+	}
+
+	return changes
+}
+
+// compareEdges matches the edges by pair of node keys, then by signature
+func (r *Report) compareEdges() {
+	oldEdges := indexEdges(r.old)
+	newEdges := indexEdges(r.new)
+
+	keys := sortedKeys(newEdges)
+	for key := range oldEdges {
+		if _, exists := newEdges[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		oldPair, newPair := oldEdges[key], newEdges[key]
+		r.edges[key] = append(slices.Clone(newPair), oldPair...)
+
+		switch {
+		case len(oldPair) == 0:
+			r.AddedEdges = append(r.AddedEdges, newPair...)
+		case len(newPair) == 0:
+			r.RemovedEdges = append(r.RemovedEdges, oldPair...)
+		default:
+			oldSignatures, newSignatures := signatures(oldPair), signatures(newPair)
+			if !slices.Equal(oldSignatures, newSignatures) {
+				r.ChangedEdges = append(r.ChangedEdges, EdgeChange{
+					Source: NodeKey(newPair[0].Source),
+					Target: NodeKey(newPair[0].Target),
+					Old:    oldSignatures,
+					New:    newSignatures,
+				})
+			}
+		}
+	}
+}
+
+// compareStats compares the main statistics of both builds
+func (r *Report) compareStats() {
+	add := func(name string, value func(m *common.Metadata) string) {
+		r.StatsDeltas = append(r.StatsDeltas, StatDelta{Name: name, Old: value(r.old), New: value(r.new)})
+	}
+	count := func(value func(s common.GraphStats) int) func(m *common.Metadata) string {
+		return func(m *common.Metadata) string {
+			return fmt.Sprint(value(m.Stats))
+		}
+	}
+
+	add("stacks", func(m *common.Metadata) string { return fmt.Sprint(m.TotalStacks) })
+	add("cards", func(m *common.Metadata) string { return fmt.Sprint(m.TotalCards) })
+	add("nodes", func(m *common.Metadata) string { return fmt.Sprint(len(m.Nodes)) })
+	add("edges", func(m *common.Metadata) string { return fmt.Sprint(len(m.Edges)) })
+	add("connected components", count(func(s common.GraphStats) int { return len(s.ConnectedComponents) }))
+	add("strongly connected components", count(func(s common.GraphStats) int { return len(s.StronglyConnectedComponents) }))
+	add("nodes with no incoming edges", count(func(s common.GraphStats) int { return len(s.NodesWithNoIncoming) }))
+	add("nodes with no outgoing edges", count(func(s common.GraphStats) int { return len(s.NodesWithNoOutgoing) }))
+	add("isolated nodes", count(func(s common.GraphStats) int { return len(s.IsolatedNodes) }))
+	add("most separated nodes", func(m *common.Metadata) string {
+		pair := m.Stats.MostSeparatedNodes
+		if pair.Path == nil {
+			return "-"
+		}
+		return fmt.Sprintf("%s -> %s (%.0f)", pair.Source.Name, pair.Target.Name, pair.Distance)
+	})
+}
+
+func indexNodes(metadata *common.Metadata) map[string]*common.Node {
+	nodes := make(map[string]*common.Node, len(metadata.Nodes))
+	for _, node := range metadata.Nodes {
+		nodes[NodeKey(node)] = node
+	}
+	return nodes
+}
+
+func indexEdges(metadata *common.Metadata) map[string][]*common.Edge {
+	edges := make(map[string][]*common.Edge)
+	for _, edge := range metadata.Edges {
+		key := edgePairKey(edge)
+		edges[key] = append(edges[key], edge)
+	}
+	return edges
+}
+
+// signatures returns the sorted signatures of the edges, duplicates included
+// (e.g., a link removed from a pair of cards linked twice is reported)
+func signatures(edges []*common.Edge) []string {
+	var result []string
+	for _, edge := range edges {
+		result = append(result, edgeSignature(edge))
+	}
+	slices.Sort(result)
+	return result
+}
+
+func nodeAttributeNames(node *common.Node) []string {
+	var names []string
+	for _, attr := range node.Attributes {
+		names = append(names, attr.String())
+	}
+	return names
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
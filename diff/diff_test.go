@@ -0,0 +1,61 @@
+package diff
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// build returns the metadata of the given edges between the cards Myst:1 and Myst:2
+func build(edges ...[]common.EdgeAttribute) *common.Metadata {
+	source := &common.Node{Name: "Myst:1", StackName: "Myst", CardID: 1, Attributes: []common.NodeAttribute{common.IsCard}}
+	target := &common.Node{Name: "Myst:2", StackName: "Myst", CardID: 2, Attributes: []common.NodeAttribute{common.IsCard}}
+
+	metadata := &common.Metadata{Nodes: []*common.Node{source, target}}
+	for i, attributes := range edges {
+		metadata.Edges = append(metadata.Edges, &common.Edge{Source: source, Target: target, Attributes: attributes, TransitivityID: int64(i)})
+	}
+	return metadata
+}
+
+func TestCompareEdges(t *testing.T) {
+	link := []common.EdgeAttribute{common.IntraAge}
+	disabled := []common.EdgeAttribute{common.IntraAge, common.Disabled}
+
+	tests := []struct {
+		name     string
+		old, new *common.Metadata
+		changed  []EdgeChange
+	}{
+		{"identical", build(link, disabled), build(link, disabled), nil},
+		{"reordered (other transitivity IDs)", build(link, disabled), build(disabled, link), nil},
+		{"parallel link removed", build(link, link), build(link), []EdgeChange{
+			{Source: "Myst:1", Target: "Myst:2", Old: []string{"IntraAge", "IntraAge"}, New: []string{"IntraAge"}},
+		}},
+		{"parallel link added", build(disabled), build(disabled, disabled), []EdgeChange{
+			{Source: "Myst:1", Target: "Myst:2", Old: []string{"Disabled+IntraAge"}, New: []string{"Disabled+IntraAge", "Disabled+IntraAge"}},
+		}},
+		{"link disabled", build(link, link), build(link, disabled), []EdgeChange{
+			{Source: "Myst:1", Target: "Myst:2", Old: []string{"IntraAge", "IntraAge"}, New: []string{"Disabled+IntraAge", "IntraAge"}},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := Compare(test.old, test.new)
+
+			if len(r.AddedEdges) != 0 || len(r.RemovedEdges) != 0 {
+				t.Errorf("got %d added and %d removed edges, expected none", len(r.AddedEdges), len(r.RemovedEdges))
+			}
+			if !slices.EqualFunc(r.ChangedEdges, test.changed, func(a, b EdgeChange) bool {
+				return a.Source == b.Source && a.Target == b.Target && slices.Equal(a.Old, b.Old) && slices.Equal(a.New, b.New)
+			}) {
+				t.Errorf("got the changes %+v, expected %+v", r.ChangedEdges, test.changed)
+			}
+			if r.IsEmpty() != (len(test.changed) == 0) {
+				t.Errorf("got IsEmpty() = %v", r.IsEmpty())
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// rendering colors
+const (
+	addedColor     = "#2E7D32" // green
+	removedColor   = "#C62828" // red
+	changedColor   = "#EF6C00" // orange
+	unchangedColor = "#BDBDBD" // gray
+)
+
+// WriteDOT writes the union of both builds as a DOT graph:
+// additions in green, removals in red, changes in orange, and the rest in gray
+// NOTE: with `changedOnly`, only the changed nodes and edges (and the ends of the changed edges) are rendered
+func (r *Report) WriteDOT(w io.Writer, changedOnly bool) error {
+	nodeColors := make(map[string]string)
+	for _, node := range r.AddedNodes {
+		nodeColors[NodeKey(node)] = addedColor
+	}
+	for _, node := range r.RemovedNodes {
+		nodeColors[NodeKey(node)] = removedColor
+	}
+	for _, change := range r.ChangedNodes {
+		nodeColors[NodeKey(change.New)] = changedColor
+	}
+
+	edgeColors := make(map[*common.Edge]string)
+	for _, edge := range r.AddedEdges {
+		edgeColors[edge] = addedColor
+	}
+	for _, edge := range r.RemovedEdges {
+		edgeColors[edge] = removedColor
+	}
+	changedPairs := make(map[string]bool)
+	for _, change := range r.ChangedEdges {
+		changedPairs[fmt.Sprintf("%s -> %s", change.Source, change.Target)] = true
+	}
+
+	// edges to render: for the changed pairs, the old edges are rendered as removed
+	// and the new ones as added; otherwise, the edges of the new build
+	var edges []*common.Edge
+	for _, key := range sortedKeys(r.edges) {
+		for _, edge := range r.edges[key] {
+			_, exists := edgeColors[edge]
+			switch {
+			case changedPairs[key]:
+				if r.isOldEdge(edge) {
+					edgeColors[edge] = removedColor
+				} else {
+					edgeColors[edge] = addedColor
+				}
+			case exists:
+			case r.isOldEdge(edge):
+				continue // unchanged edge, already rendered from the new build
+			default:
+				if changedOnly {
+					continue
+				}
+				edgeColors[edge] = unchangedColor
+			}
+			edges = append(edges, edge)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph MystGraphDiff {\n")
+	b.WriteString("  graph [overlap=false, splines=true, outputorder=edgesfirst];\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fillcolor=white, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=8];\n")
+
+	rendered := make(map[string]bool)
+	if changedOnly {
+		for key := range nodeColors {
+			rendered[key] = true
+		}
+		for _, edge := range edges {
+			rendered[NodeKey(edge.Source)] = true
+			rendered[NodeKey(edge.Target)] = true
+		}
+	}
+
+	for _, key := range sortedKeys(r.nodes) {
+		if changedOnly && !rendered[key] {
+			continue
+		}
+
+		color, exists := nodeColors[key]
+		if !exists {
+			color = unchangedColor
+		}
+		penWidth := 1.0
+		if exists {
+			penWidth = 3.0
+		}
+		fmt.Fprintf(&b, "  \"%s\" [color=\"%s\", fontcolor=\"%s\", penwidth=%.1f, tooltip=\"%s\"];\n",
+			escape(key), color, color, penWidth, escape(r.nodeTooltip(key)))
+	}
+
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edgePairKey(edges[i]) < edgePairKey(edges[j])
+	})
+	for _, edge := range edges {
+		color := edgeColors[edge]
+		penWidth := 1.0
+		if color != unchangedColor {
+			penWidth = 2.5
+		}
+		fmt.Fprintf(&b, "  \"%s\" -> \"%s\" [color=\"%s\", penwidth=%.1f, tooltip=\"%s\"];\n",
+			escape(NodeKey(edge.Source)), escape(NodeKey(edge.Target)), color, penWidth,
+			escape(formatSignature(edgeSignature(edge))))
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// isOldEdge reports whether the edge belongs to the old build
+func (r *Report) isOldEdge(edge *common.Edge) bool {
+	return r.oldEdges[edge]
+}
+
+// nodeTooltip describes the changes of a node
+func (r *Report) nodeTooltip(key string) string {
+	for _, change := range r.ChangedNodes {
+		if NodeKey(change.New) == key {
+			return strings.Join(change.Changes, ", ")
+		}
+	}
+	return key
+}
+
+func escape(input string) string {
+	result := strings.ReplaceAll(input, "\\", "\\\\")
+	return strings.ReplaceAll(result, "\"", "\\\"")
+}
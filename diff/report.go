@@ -0,0 +1,77 @@
+package diff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteText writes the report as plain text
+func (r *Report) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintln(&b, "Statistics (old -> new):")
+	for _, delta := range r.StatsDeltas {
+		marker := " "
+		if delta.Changed() {
+			marker = "*"
+		}
+		fmt.Fprintf(&b, "%s %-30s %s -> %s\n", marker, delta.Name, delta.Old, delta.New)
+	}
+
+	fmt.Fprintf(&b, "\nAdded nodes (%d):\n", len(r.AddedNodes))
+	for _, node := range r.AddedNodes {
+		fmt.Fprintf(&b, "+ %s\n", NodeKey(node))
+	}
+
+	fmt.Fprintf(&b, "\nRemoved nodes (%d):\n", len(r.RemovedNodes))
+	for _, node := range r.RemovedNodes {
+		fmt.Fprintf(&b, "- %s\n", NodeKey(node))
+	}
+
+	fmt.Fprintf(&b, "\nChanged nodes (%d):\n", len(r.ChangedNodes))
+	for _, change := range r.ChangedNodes {
+		fmt.Fprintf(&b, "~ %s: %s\n", NodeKey(change.New), strings.Join(change.Changes, ", "))
+	}
+
+	fmt.Fprintf(&b, "\nAdded edges (%d):\n", len(r.AddedEdges))
+	for _, edge := range r.AddedEdges {
+		fmt.Fprintf(&b, "+ %s %s\n", edgePairKey(edge), formatSignature(edgeSignature(edge)))
+	}
+
+	fmt.Fprintf(&b, "\nRemoved edges (%d):\n", len(r.RemovedEdges))
+	for _, edge := range r.RemovedEdges {
+		fmt.Fprintf(&b, "- %s %s\n", edgePairKey(edge), formatSignature(edgeSignature(edge)))
+	}
+
+	fmt.Fprintf(&b, "\nChanged edges (%d):\n", len(r.ChangedEdges))
+	for _, change := range r.ChangedEdges {
+		fmt.Fprintf(&b, "~ %s -> %s: %s -> %s\n", change.Source, change.Target,
+			formatSignatures(change.Old), formatSignatures(change.New))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Summary returns a one-line summary of the report
+func (r *Report) Summary() string {
+	return fmt.Sprintf("nodes: +%d -%d ~%d, edges: +%d -%d ~%d",
+		len(r.AddedNodes), len(r.RemovedNodes), len(r.ChangedNodes),
+		len(r.AddedEdges), len(r.RemovedEdges), len(r.ChangedEdges))
+}
+
+func formatSignature(signature string) string {
+	if signature == "" {
+		return "[]"
+	}
+	return "[" + signature + "]"
+}
+
+func formatSignatures(signatures []string) string {
+	formatted := make([]string, 0, len(signatures))
+	for _, signature := range signatures {
+		formatted = append(formatted, formatSignature(signature))
+	}
+	return strings.Join(formatted, " ")
+}
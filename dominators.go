@@ -0,0 +1,107 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/glthr/DeMystify/config"
+	"github.com/glthr/DeMystify/graph"
+	"github.com/glthr/DeMystify/renderer/dot"
+)
+
+// runDominators prints the cards every player must pass through to reach the given cards
+// (dominators), computed from the entry card
+func runDominators(args []string) {
+	flags := flag.NewFlagSet(dominatorsCommand, flag.ExitOnError)
+	graphOptions := registerGraphFlags(flags)
+	entry := flags.String("entry", "", "entry card `{stack}:{card id}` (default: the profile entry card)")
+	var targets []config.CardRef
+	flags.Func("node", "card `{stack}:{card id}` to analyze, repeatable (default: the profile goal cards)", func(value string) error {
+		ref, err := config.ParseCardRef(value)
+		if err != nil {
+			return err
+		}
+		targets = append(targets, ref)
+		return nil
+	})
+	render := flags.Bool("render", false, "render the dominator tree in "+dominatorsPath)
+	_ = flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		log.Fatal("Usage: go run . dominators [-profile <name|profile.json>] [-rules <rules.json>] [-continue-on-error] [-cost-model <preset>] [-no-cache] [-entry <stack:id>] [-node <stack:id>]... [-render] <xml_hypercard_files_directory_path>")
+	}
+
+	if *render {
+		if err := CheckNeatoInstalled(); err != nil {
+			log.Fatalf("Neato is not installed: %v", err)
+		}
+	}
+
+	loaded := loadGraph(flags.Arg(0), graphOptions, graph.DefaultCycleOptions())
+	g := loaded.graph
+
+	entryID, err := dominatorsEntryID(g, *entry)
+	if err != nil {
+		log.Fatalf("invalid entry card: %v", err)
+	}
+
+	tree, err := g.ComputeDominators(entryID)
+	if err != nil {
+		log.Fatalf("unable to compute the dominators: %v", err)
+	}
+
+	if len(targets) == 0 {
+		targets = loaded.profile.GoalNodes
+	}
+
+	fmt.Printf("Dominators from %s:\n", g.GetNameForID(entryID))
+	for _, target := range targets {
+		targetID, err := g.GetCardNodeID(target)
+		if err != nil {
+			log.Printf("unknown card: %v", err)
+			continue
+		}
+
+		fmt.Printf("%s:\n", target)
+		if !tree.Contains(targetID) {
+			fmt.Println("  unreachable from the entry card")
+			continue
+		}
+
+		if idom, ok := tree.ImmediateDominator(targetID); ok {
+			fmt.Printf("  immediate dominator: %s\n", g.GetNameForID(idom))
+		}
+		fmt.Printf("  mandatory cards (%d): %v\n", len(tree.Dominators(targetID)), g.FormatPathAsNames(tree.Dominators(targetID)))
+		fmt.Printf("  dominance frontier: %v\n", g.FormatPathAsNames(tree.DominanceFrontier(targetID)))
+	}
+
+	if *render {
+		fmt.Println("Generating the dominator tree...")
+
+		dotConfig := dot.DefaultConfig()
+		dotConfig.TagStyles = loaded.profile.Rules.Styles
+		dotConfig.StackDisplayNames = loaded.profile.StackDisplayNames
+		dotConfig.DominatorTree = tree
+
+		RenderAlternateGraph(g, loaded.metadata, dotConfig, dominatorsPath)
+	}
+
+	if loaded.parseErrors.HasErrors() {
+		os.Exit(exitPartialSuccess)
+	}
+}
+
+// dominatorsEntryID returns the ID of the entry card (the profile entry card by default)
+func dominatorsEntryID(g *graph.MystGraph, entry string) (int64, error) {
+	if entry == "" {
+		return g.EntryNodeID()
+	}
+
+	ref, err := config.ParseCardRef(entry)
+	if err != nil {
+		return -1, err
+	}
+	return g.GetCardNodeID(ref)
+}
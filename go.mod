@@ -2,6 +2,7 @@ module github.com/glthr/DeMystify
 
 go 1.24.1
 
-require gonum.org/v1/gonum v0.15.1
-
-require golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa // indirect
+require (
+	golang.org/x/exp v0.0.0-20231110203233-9a3e6036ecaa
+	gonum.org/v1/gonum v0.15.1
+)
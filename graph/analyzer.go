@@ -1,7 +1,15 @@
 package graph
 
-// Process analyzes the Myst Graph on three dimensions: nodes, components, and paths
-func (g *MystGraph) Process() {
+import "github.com/glthr/DeMystify/config"
+
+// Process analyzes the Myst Graph on three dimensions: nodes, components, and paths,
+// against the given game profile
+func (g *MystGraph) Process(profile *config.Profile) {
+	g.Profile = profile
+
+	// profile
+	g.markProfileNodes()
+
 	// nodes
 	g.Metadata.Stats.MostIncomingNode = g.FindNodeWithMostIncomingEdges()
 	g.Metadata.Stats.MostOutgoingNode = g.FindNodeWithMostOutgoingEdges()
@@ -9,11 +17,35 @@ func (g *MystGraph) Process() {
 	g.Metadata.Stats.NodesWithNoOutgoing = g.FindNodesWithNoOutgoingEdges()
 	g.Metadata.Stats.IsolatedNodes = g.FindIsolatedNodes()
 	g.Metadata.Stats.NodesWithSelfLoops = g.FindNodesWithSelfLoops()
+	g.Metadata.Stats.Centrality = g.ComputeCentrality()
 
 	// components
 	g.Metadata.Stats.ConnectedComponents = g.FindConnectedComponents()
+	g.Metadata.Stats.StronglyConnectedComponents, g.Metadata.Stats.CondensationEdges = g.FindStronglyConnectedComponents()
+	g.Metadata.Stats.Communities = g.DetectCommunities()
+	g.Metadata.Stats.Cycles, g.Metadata.Stats.CyclesTruncated = g.FindCycles()
+	g.Metadata.Stats.Positions = g.ReconstructPositions()
+	g.Metadata.Stats.AgeQuotient = g.ComputeAgeQuotient()
+
+	// robustness
+	g.Metadata.Stats.Bridges, g.Metadata.Stats.ArticulationPoints = g.undirectedCutsAnalysis()
+	g.Metadata.Stats.StackConnectivity = g.ComputeStackConnectivity()
 
 	// paths
 	g.Metadata.Stats.ShortestPaths = g.ComputeAllShortestPaths()
 	g.Metadata.Stats.MostSeparatedNodes = g.FindMostSeparatedNodes()
+	g.Metadata.Stats.GoalPaths = g.ComputeGoalPaths()
+	g.Metadata.Stats.Pushdown = g.ComputePushdownStats()
+	g.Metadata.Stats.Reachability = g.ComputeReachability()
+
+	// metrics
+	g.Metadata.Stats.Metrics = g.ComputeMetrics()
+}
+
+// Restore sets the profile of a graph whose metadata already contains the results of Process
+// (e.g., loaded from a cache), instead of analyzing it again
+// NOTE: the cost model must be set first (see SetCostModel)
+func (g *MystGraph) Restore(profile *config.Profile) {
+	g.Profile = profile
+	g.pathAnalyzer.distances = g.Metadata.Stats.ShortestPaths
 }
@@ -2,8 +2,9 @@ package graph
 
 import (
 	"fmt"
+
 	"github.com/glthr/DeMystify/common"
-	"math"
+	"github.com/glthr/DeMystify/config"
 
 	gograph "gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/multi"
@@ -16,6 +17,11 @@ type MystGraph struct {
 	EdgeMap    map[int64]map[int64][]common.Edge
 	IdNameMap  map[int64]string
 	IdStackMap map[int64]string
+	Profile    *config.Profile
+	// CycleOptions bounds the enumeration of the cycles (see FindCycles)
+	CycleOptions CycleOptions
+	// CostModel weights the edges (see SetCostModel)
+	CostModel CostModel
 
 	traverser    *traverser
 	nodeAnalyzer *nodesAnalyzer
@@ -34,6 +40,10 @@ func NewGraph(metadata *common.Metadata) (*MystGraph, error) {
 		EdgeMap:    make(map[int64]map[int64][]common.Edge),
 		IdNameMap:  make(map[int64]string, len(metadata.Nodes)),
 		IdStackMap: make(map[int64]string, len(metadata.Nodes)),
+		Profile:    config.MystProfile(),
+
+		CycleOptions: DefaultCycleOptions(),
+		CostModel:    DefaultCostModel(),
 	}
 
 	// add nodes
@@ -62,16 +72,13 @@ func NewGraph(metadata *common.Metadata) (*MystGraph, error) {
 	return g, nil
 }
 
-// addEdgeWithAttributes adds an edge to the graph based on its attributes
+// addEdgeWithAttributes adds an edge to the graph based on its attributes,
+// weighted by the cost model
 func (g *MystGraph) addEdgeWithAttributes(edge *common.Edge) error {
-	switch {
-	case edge.IsOfType(common.Disabled):
+	if edge.IsOfType(common.Disabled) {
 		return g.addDisabledEdge(edge)
-	case edge.IsOfType(common.Backtracking):
-		return g.AddEdge(edge, math.Inf(1))
-	default:
-		return g.AddEdge(edge, 1.0)
 	}
+	return g.AddEdge(edge, g.CostModel.Cost(edge))
 }
 
 // addDisabledEdge adds a disabled edge to the EdgeMap without adding it to the graph
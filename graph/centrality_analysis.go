@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
+
+	"gonum.org/v1/gonum/graph/network"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+const (
+	pageRankDamping   = 0.85
+	pageRankTolerance = 1e-8
+
+	eigenvectorIterations = 1000
+	eigenvectorTolerance  = 1e-9
+)
+
+// ComputeCentrality calculates the centrality metrics of every node
+// NOTE: the degrees are computed on the traversable view of the graph (no backtracking nor
+// disabled edges, no self-loops, parallel edges merged), and the other metrics on the state
+// graph (see weightedGraph), so that the paths and the walks through a transitive card follow
+// the Head edge paired with the Tail edge; the scores of the pending states are added to their
+// transitive cards
+func (g *MystGraph) ComputeCentrality() common.CentralityStats {
+	view := g.traversableGraph()
+	states := g.pathAnalyzer.weightedGraph()
+
+	nodeIDs := g.traverser.getAllNodeIDs()
+	n := float64(len(nodeIDs))
+
+	betweenness, closeness := stateBetweenness(states, nodeIDs)
+	pageRank := collapseStates(states, network.PageRankSparse(states, pageRankDamping, pageRankTolerance))
+	eigenvector := collapseStates(states, eigenvectorCentrality(states.WeightedDirectedGraph))
+	normalize(eigenvector)
+
+	stats := common.CentralityStats{
+		Nodes:                 make(map[int64]common.NodeCentrality, len(nodeIDs)),
+		InDegreeDistribution:  make(map[int]int),
+		OutDegreeDistribution: make(map[int]int),
+	}
+
+	for _, id := range nodeIDs {
+		c := common.NodeCentrality{
+			ID:          id,
+			Name:        g.GetNameForID(id),
+			InDegree:    view.To(id).Len(),
+			OutDegree:   view.From(id).Len(),
+			PageRank:    pageRank[id],
+			Eigenvector: eigenvector[id],
+		}
+
+		// normalize by the number of ordered node pairs (directed graph)
+		if n > 2 {
+			c.Betweenness = betweenness[id] / ((n - 1) * (n - 2))
+		}
+		if n > 1 {
+			c.Closeness = closeness[id] / (n - 1)
+		}
+
+		stats.Nodes[id] = c
+		stats.InDegreeDistribution[c.InDegree]++
+		stats.OutDegreeDistribution[c.OutDegree]++
+	}
+
+	return stats
+}
+
+// stateBetweenness computes the betweenness (Brandes) and the harmonic closeness (based on the
+// incoming paths) of the nodes on the state graph, the paths going from a node to another one
+// NOTE: the shortest paths are ordered by their settling order, so that the zero-cost edges
+// cannot create cycles of shortest paths
+func stateBetweenness(view *stateGraph, nodeIDs []int64) (betweenness, closeness map[int64]float64) {
+	states, adjacency, _ := stateAdjacency(view, nodeIDs)
+
+	cards := make([]int64, len(states))
+	for i, id := range states {
+		cards[i] = view.card(id)
+	}
+
+	betweenness = make(map[int64]float64, len(nodeIDs))
+	closeness = make(map[int64]float64, len(nodeIDs))
+
+	distances := make([]float64, len(states))
+	paths := make([]float64, len(states)) // number of shortest paths from the source
+	dependencies := make([]float64, len(states))
+	settled := make([]bool, len(states))
+	predecessors := make([][]int32, len(states))
+
+	for source := range int32(len(nodeIDs)) {
+		for i := range states {
+			distances[i] = math.Inf(1)
+			paths[i] = 0
+			dependencies[i] = 0
+			settled[i] = false
+			predecessors[i] = predecessors[i][:0]
+		}
+		distances[source] = 0
+		paths[source] = 1
+
+		var order []int32
+		queue := &stateQueue{{state: source}}
+		for queue.Len() > 0 {
+			item := heap.Pop(queue).(stateQueueItem)
+			if settled[item.state] || item.distance > distances[item.state] {
+				continue // outdated item
+			}
+			settled[item.state] = true
+			order = append(order, item.state)
+
+			for _, a := range adjacency[item.state] {
+				if settled[a.to] {
+					continue
+				}
+
+				switch distance := item.distance + a.weight; {
+				case distance < distances[a.to]:
+					distances[a.to] = distance
+					paths[a.to] = paths[item.state]
+					predecessors[a.to] = append(predecessors[a.to][:0], item.state)
+					heap.Push(queue, stateQueueItem{state: a.to, distance: distance})
+				case distance == distances[a.to]:
+					paths[a.to] += paths[item.state]
+					predecessors[a.to] = append(predecessors[a.to], item.state)
+				}
+			}
+		}
+
+		// accumulate the dependencies of the source on the states, from the farthest ones
+		for i := len(order) - 1; i > 0; i-- {
+			state := order[i]
+
+			target := 0.0
+			if int(state) < len(nodeIDs) {
+				target = 1
+				closeness[cards[state]] += 1 / distances[state]
+			}
+
+			coefficient := (target + dependencies[state]) / paths[state]
+			for _, predecessor := range predecessors[state] {
+				dependencies[predecessor] += paths[predecessor] * coefficient
+			}
+
+			if cards[state] != cards[source] {
+				betweenness[cards[state]] += dependencies[state]
+			}
+		}
+	}
+
+	return betweenness, closeness
+}
+
+// collapseStates adds the scores of the pending states to their transitive cards
+func collapseStates(view *stateGraph, scores map[int64]float64) map[int64]float64 {
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids) // deterministic sums
+
+	collapsed := make(map[int64]float64, len(scores))
+	for _, id := range ids {
+		collapsed[view.card(id)] += scores[id]
+	}
+	return collapsed
+}
+
+// normalize scales the scores to a unit Euclidean norm
+func normalize(scores map[int64]float64) {
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	norm := 0.0
+	for _, id := range ids {
+		norm += scores[id] * scores[id]
+	}
+	if norm == 0 {
+		return
+	}
+
+	norm = math.Sqrt(norm)
+	for _, id := range ids {
+		scores[id] /= norm
+	}
+}
+
+// eigenvectorCentrality computes the eigenvector centrality (based on the incoming edges)
+// by power iteration
+// NOTE: the iteration is shifted (x <- Aᵀx + x) so that it converges on graphs that are
+// neither strongly connected nor aperiodic, which is the case of the Myst Graph
+func eigenvectorCentrality(view *simple.WeightedDirectedGraph) map[int64]float64 {
+	var nodeIDs []int64
+	nodes := view.Nodes()
+	for nodes.Next() {
+		nodeIDs = append(nodeIDs, nodes.Node().ID())
+	}
+	slices.Sort(nodeIDs)
+
+	if len(nodeIDs) == 0 {
+		return map[int64]float64{}
+	}
+
+	x := make(map[int64]float64, len(nodeIDs))
+	for _, id := range nodeIDs {
+		x[id] = 1 / math.Sqrt(float64(len(nodeIDs)))
+	}
+
+	for range eigenvectorIterations {
+		next := make(map[int64]float64, len(nodeIDs))
+		norm := 0.0
+		for _, id := range nodeIDs {
+			value := x[id]
+			predecessors := view.To(id)
+			for predecessors.Next() {
+				value += x[predecessors.Node().ID()]
+			}
+			next[id] = value
+			norm += value * value
+		}
+
+		norm = math.Sqrt(norm)
+		delta := 0.0
+		for _, id := range nodeIDs {
+			next[id] /= norm
+			delta += math.Abs(next[id] - x[id])
+		}
+
+		x = next
+		if delta < eigenvectorTolerance {
+			break
+		}
+	}
+
+	return x
+}
@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+
+	"gonum.org/v1/gonum/graph/network"
+	"gonum.org/v1/gonum/graph/path"
+)
+
+func TestStateBetweenness(t *testing.T) {
+	t.Run("without transitivity", func(t *testing.T) {
+		for seed := range int64(5) {
+			f := newFixture()
+			random := randomFixture(seed, 40, 0.08)
+			for _, edge := range random.metadata.Edges {
+				if !edge.IsOfType(common.RestrictiveTransitivityTail) && !edge.IsOfType(common.RestrictiveTransitivityHead) {
+					f.link(edge.Source.Name, edge.Target.Name, edge.Attributes[1:]...)
+				}
+			}
+			for name := range random.nodes {
+				f.card(name)
+			}
+			g := f.graph(t, "")
+
+			betweenness, closeness := stateBetweenness(g.pathAnalyzer.weightedGraph(), g.traverser.getAllNodeIDs())
+
+			// the reference: gonum, on the traversable view (the same graph without transitivity)
+			view := g.traversableGraph()
+			allPaths := path.DijkstraAllPaths(view)
+			expectedBetweenness := network.BetweennessWeighted(view, allPaths)
+			expectedCloseness := network.Harmonic(view, allPaths)
+
+			for _, id := range g.traverser.getAllNodeIDs() {
+				if math.Abs(betweenness[id]-expectedBetweenness[id]) > 1e-9 {
+					t.Errorf("seed %d: %s has the betweenness %v, expected %v", seed, g.GetNameForID(id), betweenness[id], expectedBetweenness[id])
+				}
+				if math.Abs(closeness[id]-expectedCloseness[id]) > 1e-9 {
+					t.Errorf("seed %d: %s has the closeness %v, expected %v", seed, g.GetNameForID(id), closeness[id], expectedCloseness[id])
+				}
+			}
+		}
+	})
+
+	t.Run("crossed transitivity", func(t *testing.T) {
+		f, _ := crossedTransitivity()
+		g := f.graph(t, "")
+
+		betweenness, closeness := stateBetweenness(g.pathAnalyzer.weightedGraph(), g.traverser.getAllNodeIDs())
+
+		// the only paths are the subpaths of Myst:1 -> Myst:41, which passes through Myst:10 and
+		// Myst:11 twice; the transitive cards are not reached (passed through only)
+		expected := map[string]struct{ betweenness, closeness float64 }{
+			"Myst:1":  {0, 0},
+			"Myst:10": {5 + 8, 0},
+			"Myst:20": {4, 1.0 / 2},
+			"Myst:11": {8 + 5, 0},
+			"Myst:40": {6, 1.0/4 + 1.0/2},
+			"Myst:2":  {6, 1.0/5 + 1.0/3 + 1},
+			"Myst:30": {4, 1.0/7 + 1.0/5 + 1.0/3 + 1.0/2},
+			"Myst:41": {0, 1.0/9 + 1.0/7 + 1.0/5 + 1.0/4 + 1.0/2},
+		}
+		for name, values := range expected {
+			if got := betweenness[f.id(name)]; math.Abs(got-values.betweenness) > 1e-9 {
+				t.Errorf("%s has the betweenness %v, expected %v", name, got, values.betweenness)
+			}
+			if got := closeness[f.id(name)]; math.Abs(got-values.closeness) > 1e-9 {
+				t.Errorf("%s has the closeness %v, expected %v", name, got, values.closeness)
+			}
+		}
+	})
+}
+
+func TestComputeCentrality(t *testing.T) {
+	f := newFixture()
+
+	// two clusters joined by a corridor card (Myst:5), the disabled and backtracking
+	// links being ignored
+	for _, cluster := range [][]string{{"Myst:1", "Myst:2", "Myst:3"}, {"Myst:11", "Myst:12", "Myst:13"}} {
+		for _, from := range cluster {
+			for _, to := range cluster {
+				if from != to {
+					f.link(from, to)
+				}
+			}
+		}
+	}
+	f.path("Myst:3", "Myst:5", "Myst:11", "Myst:5", "Myst:3")
+	f.link("Myst:1", "Myst:13", common.Disabled)
+	f.link("Myst:12", "Myst:1", common.Backtracking)
+	g := f.graph(t, "")
+
+	stats := g.ComputeCentrality()
+
+	ranked := stats.Ranked(common.BetweennessCentrality, 3)
+	var rankedNames []string
+	for _, c := range ranked {
+		rankedNames = append(rankedNames, c.Name)
+	}
+	if strings.Join(rankedNames, " ") != "Myst:5 Myst:3 Myst:11" {
+		t.Errorf("got the betweenness ranking %v, expected Myst:5 Myst:3 Myst:11", rankedNames)
+	}
+
+	// the corridor is on the paths between the clusters (3 x 3 ordered pairs in both directions),
+	// normalized by the 6 x 5 ordered pairs of the other cards
+	if corridor := stats.Nodes[f.id("Myst:5")]; math.Abs(corridor.Betweenness-18.0/30) > 1e-9 || corridor.InDegree != 2 || corridor.OutDegree != 2 {
+		t.Errorf("got the corridor %+v, expected the betweenness 0.6 and the degrees 2", corridor)
+	}
+
+	if stats.InDegreeDistribution[2] != 5 || stats.InDegreeDistribution[3] != 2 || stats.OutDegreeDistribution[2] != 5 || stats.OutDegreeDistribution[3] != 2 {
+		t.Errorf("got the degree distributions %v and %v", stats.InDegreeDistribution, stats.OutDegreeDistribution)
+	}
+
+	pageRank, norm := 0.0, 0.0
+	for _, c := range stats.Nodes {
+		pageRank += c.PageRank
+		norm += c.Eigenvector * c.Eigenvector
+	}
+	if math.Abs(pageRank-1) > 1e-6 || math.Abs(norm-1) > 1e-6 {
+		t.Errorf("got the PageRank sum %v and the eigenvector norm %v, expected 1", pageRank, norm)
+	}
+
+	// the clusters are symmetrical
+	for _, pair := range [][2]string{{"Myst:1", "Myst:13"}, {"Myst:2", "Myst:12"}, {"Myst:3", "Myst:11"}} {
+		a, b := stats.Nodes[f.id(pair[0])], stats.Nodes[f.id(pair[1])]
+		for _, metric := range common.CentralityMetrics {
+			if math.Abs(a.Value(metric)-b.Value(metric)) > 1e-6 {
+				t.Errorf("%s: %s has %v, %s has %v", metric, pair[0], a.Value(metric), pair[1], b.Value(metric))
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/glthr/DeMystify/common"
+
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/graph/community"
+)
+
+const (
+	// communityResolution is the resolution of the modularity (1: standard modularity)
+	communityResolution = 1.0
+	// communitySeed seeds the Louvain algorithm for reproducible communities
+	communitySeed = 1993
+)
+
+// DetectCommunities detects the communities of the graph (Louvain modularity optimization),
+// and compares them with the stacks
+// NOTE: the communities are detected on the traversable view of the graph (no backtracking
+// nor disabled edges, no self-loops, parallel edges merged), as a directed graph
+func (g *MystGraph) DetectCommunities() common.CommunityStats {
+	view := g.traversableGraph()
+	reduced := community.Modularize(view, communityResolution, rand.NewSource(communitySeed))
+
+	var communities [][]int64
+	for _, nodes := range reduced.Communities() {
+		if len(nodes) == 0 {
+			continue
+		}
+
+		ids := make([]int64, 0, len(nodes))
+		for _, node := range nodes {
+			ids = append(ids, node.ID())
+		}
+		slices.Sort(ids)
+		communities = append(communities, ids)
+	}
+
+	// sort the communities deterministically:
+	// 1. by size (largest first)
+	// 2. for equal sizes, by the smallest node ID
+	sort.Slice(communities, func(i, j int) bool {
+		if len(communities[i]) != len(communities[j]) {
+			return len(communities[i]) > len(communities[j])
+		}
+		return communities[i][0] < communities[j][0]
+	})
+
+	stats := common.CommunityStats{
+		Communities:   communities,
+		NodeCommunity: make(map[int64]int),
+		Modularity:    community.Q(view, reduced.Communities(), communityResolution),
+	}
+
+	for c, ids := range communities {
+		for _, id := range ids {
+			stats.NodeCommunity[id] = c
+		}
+	}
+
+	// confusion matrix: stacks (rows) x communities (columns)
+	stackIndex := make(map[string]int)
+	for _, id := range g.traverser.getAllNodeIDs() {
+		stack := g.StackOf(id)
+		if _, exists := stackIndex[stack]; !exists {
+			stackIndex[stack] = 0
+			stats.Stacks = append(stats.Stacks, stack)
+		}
+	}
+	sort.Strings(stats.Stacks)
+	for i, stack := range stats.Stacks {
+		stackIndex[stack] = i
+	}
+
+	stats.ConfusionMatrix = make([][]int, len(stats.Stacks))
+	for i := range stats.ConfusionMatrix {
+		stats.ConfusionMatrix[i] = make([]int, len(communities))
+	}
+	for c, ids := range communities {
+		for _, id := range ids {
+			stats.ConfusionMatrix[stackIndex[g.StackOf(id)]][c]++
+		}
+	}
+
+	stats.NMI = normalizedMutualInformation(stats.ConfusionMatrix)
+
+	// disagreements: nodes outside the main stack of their community
+	// (ties are broken by stack name)
+	for c, ids := range communities {
+		mainStack := 0
+		for s := range stats.Stacks {
+			if stats.ConfusionMatrix[s][c] > stats.ConfusionMatrix[mainStack][c] {
+				mainStack = s
+			}
+		}
+
+		for _, id := range ids {
+			if stack := g.StackOf(id); stack != stats.Stacks[mainStack] {
+				stats.Disagreements = append(stats.Disagreements, common.CommunityDisagreement{
+					Node:               common.NodeInfo{ID: id, Name: g.GetNameForID(id)},
+					Stack:              stack,
+					Community:          c,
+					CommunityMainStack: stats.Stacks[mainStack],
+				})
+			}
+		}
+	}
+
+	return stats
+}
+
+// normalizedMutualInformation computes the NMI of two partitions from their contingency table,
+// normalized by the arithmetic mean of their entropies
+func normalizedMutualInformation(table [][]int) float64 {
+	total := 0.0
+	rows := make([]float64, len(table))
+	var columns []float64
+	for i, row := range table {
+		if columns == nil {
+			columns = make([]float64, len(row))
+		}
+		for j, count := range row {
+			rows[i] += float64(count)
+			columns[j] += float64(count)
+			total += float64(count)
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+
+	entropy := func(counts []float64) float64 {
+		h := 0.0
+		for _, count := range counts {
+			if count > 0 {
+				p := count / total
+				h -= p * math.Log(p)
+			}
+		}
+		return h
+	}
+
+	mutualInformation := 0.0
+	for i, row := range table {
+		for j, count := range row {
+			if count > 0 {
+				n := float64(count)
+				mutualInformation += n / total * math.Log(n*total/(rows[i]*columns[j]))
+			}
+		}
+	}
+
+	hRows, hColumns := entropy(rows), entropy(columns)
+	if hRows+hColumns == 0 {
+		return 1 // both partitions are a single group
+	}
+
+	return 2 * mutualInformation / (hRows + hColumns)
+}
@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestNormalizedMutualInformation(t *testing.T) {
+	tests := []struct {
+		name  string
+		table [][]int
+		nmi   float64
+	}{
+		{"identical partitions", [][]int{{3, 0}, {0, 2}}, 1},
+		{"relabeled partitions", [][]int{{0, 3}, {2, 0}}, 1},
+		{"independent partitions", [][]int{{1, 1}, {1, 1}}, 0},
+		{"single group", [][]int{{4}}, 1},
+		{"empty table", nil, 0},
+		{"misplaced node", [][]int{{4, 0}, {1, 4}}, 0.5953174735228889},
+		{"split group", [][]int{{2, 0}, {1, 1}}, 0.3437110184854508},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if nmi := normalizedMutualInformation(test.table); math.Abs(nmi-test.nmi) > 1e-12 {
+				t.Errorf("got %v, expected %v", nmi, test.nmi)
+			}
+		})
+	}
+}
+
+func TestDetectCommunities(t *testing.T) {
+	// two cliques joined by a single link; Myst:9 belongs to the Channelwood clique
+	f := newFixture()
+	cliques := [][]string{
+		{"Myst:1", "Myst:2", "Myst:3", "Myst:4"},
+		{"Channelwood:10", "Channelwood:11", "Channelwood:12", "Channelwood:13", "Myst:9"},
+	}
+	for _, clique := range cliques {
+		for _, from := range clique {
+			for _, to := range clique {
+				if from != to {
+					f.link(from, to)
+				}
+			}
+		}
+	}
+	f.path("Myst:4", "Channelwood:10")
+	g := f.graph(t, "")
+
+	stats := g.DetectCommunities()
+
+	var communities []string
+	for _, ids := range stats.Communities {
+		communities = append(communities, names(g, ids))
+	}
+	expected := []string{names(g, sortedIDs(f, cliques[1])), names(g, sortedIDs(f, cliques[0]))}
+	if !slices.Equal(communities, expected) {
+		t.Fatalf("got the communities %q, expected %q", communities, expected)
+	}
+	for c, clique := range []int{1, 0} {
+		for _, name := range cliques[clique] {
+			if stats.NodeCommunity[f.id(name)] != c {
+				t.Errorf("%s is in the community %d, expected %d", name, stats.NodeCommunity[f.id(name)], c)
+			}
+		}
+	}
+
+	if stats.Modularity <= 0.3 {
+		t.Errorf("got the modularity %v, expected a clear community structure", stats.Modularity)
+	}
+
+	if !slices.Equal(stats.Stacks, []string{"Channelwood", "Myst"}) {
+		t.Errorf("got the stacks %q", stats.Stacks)
+	}
+	if table := [][]int{{4, 0}, {1, 4}}; !slices.EqualFunc(stats.ConfusionMatrix, table, slices.Equal) {
+		t.Errorf("got the confusion matrix %v, expected %v", stats.ConfusionMatrix, table)
+	}
+	if math.Abs(stats.NMI-0.5953174735228889) > 1e-12 {
+		t.Errorf("got the NMI %v", stats.NMI)
+	}
+
+	if len(stats.Disagreements) != 1 {
+		t.Fatalf("got %d disagreements, expected Myst:9 only", len(stats.Disagreements))
+	}
+	if disagreement := stats.Disagreements[0]; disagreement.Node.Name != "Myst:9" || disagreement.Stack != "Myst" ||
+		disagreement.Community != 0 || disagreement.CommunityMainStack != "Channelwood" {
+		t.Errorf("got the disagreement %+v", disagreement)
+	}
+}
+
+// sortedIDs returns the graph IDs of the cards, sorted
+func sortedIDs(f *fixture, cards []string) []int64 {
+	var ids []int64
+	for _, name := range cards {
+		ids = append(ids, f.id(name))
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -56,20 +56,10 @@ func (t *traverser) getSortedNeighbors(nodeID int64, outgoing bool) []int64 {
 }
 
 // bfsComponent performs BFS from a start node
-// NOTE: uses slices instead of maps for deterministic behavior
+// NOTE: neighbors are processed in sorted order for deterministic behavior
+// (node IDs are sparse, hence the visited map)
 func (t *traverser) bfsComponent(startID int64, nodeIDs []int64) []common.NodeInfo {
-	idMap := make(map[int64]int)
-	for i, id := range nodeIDs {
-		idMap[id] = i
-	}
-
-	maxID := 0
-	for _, id := range nodeIDs {
-		if int(id) > maxID {
-			maxID = int(id)
-		}
-	}
-	visited := make([]bool, maxID+1)
+	visited := make(map[int64]bool, len(nodeIDs))
 
 	var component []common.NodeInfo
 	queue := []int64{startID}
@@ -88,7 +78,7 @@ func (t *traverser) bfsComponent(startID int64, nodeIDs []int64) []common.NodeIn
 
 		// process outgoing neighbors in sorted order
 		for _, neighborID := range outNeighbors {
-			if neighborID < int64(len(visited)) && !visited[neighborID] {
+			if !visited[neighborID] {
 				visited[neighborID] = true
 				component = append(component, common.NodeInfo{
 					ID:   neighborID,
@@ -103,7 +93,7 @@ func (t *traverser) bfsComponent(startID int64, nodeIDs []int64) []common.NodeIn
 
 		// process incoming neighbors in sorted order
 		for _, neighborID := range inNeighbors {
-			if neighborID < int64(len(visited)) && !visited[neighborID] {
+			if !visited[neighborID] {
 				visited[neighborID] = true
 				component = append(component, common.NodeInfo{
 					ID:   neighborID,
@@ -125,25 +115,16 @@ func (t *traverser) bfsComponent(startID int64, nodeIDs []int64) []common.NodeIn
 // findComponents identifies all connected components in the graph
 func (t *traverser) findComponents() [][]common.NodeInfo {
 	nodeIDs := t.getAllNodeIDs()
-
-	maxID := int64(0)
-	for _, id := range nodeIDs {
-		if id > maxID {
-			maxID = id
-		}
-	}
-	visited := make([]bool, maxID+1)
+	visited := make(map[int64]bool, len(nodeIDs))
 
 	var components [][]common.NodeInfo
 
 	for _, id := range nodeIDs {
-		if id < int64(len(visited)) && !visited[id] {
+		if !visited[id] {
 			component := t.bfsComponent(id, nodeIDs)
 
 			for _, node := range component {
-				if node.ID < int64(len(visited)) {
-					visited[node.ID] = true
-				}
+				visited[node.ID] = true
 			}
 
 			components = append(components, component)
@@ -203,21 +184,14 @@ func (g *MystGraph) FindConnectedComponents() [][]int64 {
 		return allNodeIDs[i] < allNodeIDs[j]
 	})
 
-	maxID := int64(0)
-	for _, id := range allNodeIDs {
-		if id > maxID {
-			maxID = id
-		}
-	}
-
-	visited := make([]bool, maxID+1)
+	visited := make(map[int64]bool, len(allNodeIDs))
 
 	var components [][]int64
 
 	// process nodes in deterministic order
 	for _, startID := range allNodeIDs {
-		if int(startID) >= len(visited) || visited[startID] {
-			continue // skip if already visited or out of bounds
+		if visited[startID] {
+			continue // skip if already visited
 		}
 
 		// start a new component with BFS from this node
@@ -242,7 +216,7 @@ func (g *MystGraph) FindConnectedComponents() [][]int64 {
 			})
 
 			for _, neighbor := range outNeighbors {
-				if int(neighbor) < len(visited) && !visited[neighbor] {
+				if !visited[neighbor] {
 					visited[neighbor] = true
 					component = append(component, neighbor)
 					queue = append(queue, neighbor)
@@ -261,7 +235,7 @@ func (g *MystGraph) FindConnectedComponents() [][]int64 {
 			})
 
 			for _, neighbor := range inNeighbors {
-				if int(neighbor) < len(visited) && !visited[neighbor] {
+				if !visited[neighbor] {
 					visited[neighbor] = true
 					component = append(component, neighbor)
 					queue = append(queue, neighbor)
@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
+
+	"gonum.org/v1/gonum/graph/multi"
+)
+
+// CostModel maps an edge to its traversal cost, used as its weight by the path analyses
+// (+Inf for the edges that cannot be traversed)
+type CostModel interface {
+	Cost(edge *common.Edge) float64
+}
+
+// cost model presets (see ParseCostPreset)
+const (
+	ClicksCostPreset       = "clicks"
+	AgeSwitchesCostPreset  = "age-switches"
+	BacktrackingCostPreset = "backtracking" // `backtracking=N`
+)
+
+// ClicksCost counts the clicks: each edge costs 1, and the backtracking edges
+// cannot be traversed (default)
+type ClicksCost struct{}
+
+func (ClicksCost) Cost(edge *common.Edge) float64 {
+	if edge.IsOfType(common.Disabled) || edge.IsOfType(common.Backtracking) {
+		return math.Inf(1)
+	}
+	return 1
+}
+
+// ageSwitchTieBreak is the cost of the edges within an Age for AgeSwitchesCost
+// NOTE: a zero cost would make the paths within an Age arbitrarily long
+const ageSwitchTieBreak = 1e-3
+
+// AgeSwitchesCost counts the Age switches: each CrossAge edge costs 1, and the other
+// edges a small cost breaking the ties in favor of the fewest clicks
+type AgeSwitchesCost struct{}
+
+func (AgeSwitchesCost) Cost(edge *common.Edge) float64 {
+	switch {
+	case edge.IsOfType(common.Disabled) || edge.IsOfType(common.Backtracking):
+		return math.Inf(1)
+	case edge.IsOfType(common.CrossAge):
+		return 1
+	default:
+		return ageSwitchTieBreak
+	}
+}
+
+// BacktrackingCost counts the clicks, the backtracking edges (pop card) being
+// traversable at the given cost
+type BacktrackingCost struct {
+	Penalty float64
+}
+
+func (c BacktrackingCost) Cost(edge *common.Edge) float64 {
+	switch {
+	case edge.IsOfType(common.Disabled):
+		return math.Inf(1)
+	case edge.IsOfType(common.Backtracking):
+		return c.Penalty
+	default:
+		return 1
+	}
+}
+
+// extraCost adds the configured costs of the edge attributes, tags, and source stacks
+// to a base cost model
+type extraCost struct {
+	base       CostModel
+	attributes map[common.EdgeAttribute]float64
+	tags       map[string]float64
+	stacks     map[string]float64
+}
+
+func (c extraCost) Cost(edge *common.Edge) float64 {
+	cost := c.base.Cost(edge)
+
+	for _, attr := range edge.Attributes {
+		cost += c.attributes[attr]
+	}
+	for _, tag := range edge.Tags {
+		cost += c.tags[tag]
+	}
+
+	return cost + c.stacks[edge.Source.StackName]
+}
+
+// DefaultCostModel returns the default cost model (clicks)
+func DefaultCostModel() CostModel {
+	return ClicksCost{}
+}
+
+// ParseCostPreset returns the cost model of the given preset:
+// `clicks`, `age-switches`, or `backtracking=N`
+func ParseCostPreset(preset string) (CostModel, error) {
+	name, value, hasValue := strings.Cut(strings.ToLower(strings.TrimSpace(preset)), "=")
+
+	switch {
+	case name == "" || name == ClicksCostPreset:
+		return ClicksCost{}, nil
+	case name == AgeSwitchesCostPreset:
+		return AgeSwitchesCost{}, nil
+	case name == BacktrackingCostPreset && hasValue:
+		penalty, err := strconv.ParseFloat(value, 64)
+		if err != nil || math.IsNaN(penalty) || penalty < 0 {
+			return nil, fmt.Errorf("invalid backtracking cost %q (expected a non-negative number)", value)
+		}
+		return BacktrackingCost{Penalty: penalty}, nil
+	default:
+		return nil, fmt.Errorf("unknown cost model %q (expected clicks, age-switches, or backtracking=N)", preset)
+	}
+}
+
+// NewCostModel creates the configured cost model (the default one if nil)
+func NewCostModel(cfg *config.CostModel) (CostModel, error) {
+	if cfg == nil {
+		return DefaultCostModel(), nil
+	}
+
+	base, err := ParseCostPreset(cfg.Preset)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cfg.Attributes) == 0 && len(cfg.Tags) == 0 && len(cfg.Stacks) == 0 {
+		return base, nil
+	}
+
+	model := extraCost{
+		base:       base,
+		attributes: make(map[common.EdgeAttribute]float64, len(cfg.Attributes)),
+		tags:       cfg.Tags,
+		stacks:     cfg.Stacks,
+	}
+	for name, cost := range cfg.Attributes {
+		attr, ok := common.ParseEdgeAttribute(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown edge attribute %q in the cost model", name)
+		}
+		if err := checkExtraCost("edge attribute", name, cost); err != nil {
+			return nil, err
+		}
+		model.attributes[attr] = cost
+	}
+	for tag, cost := range cfg.Tags {
+		if err := checkExtraCost("tag", tag, cost); err != nil {
+			return nil, err
+		}
+	}
+	for stack, cost := range cfg.Stacks {
+		if err := checkExtraCost("stack", stack, cost); err != nil {
+			return nil, err
+		}
+	}
+
+	return model, nil
+}
+
+// checkExtraCost rejects the negative (or NaN) extra costs, which the shortest path
+// algorithms do not support
+func checkExtraCost(kind, name string, cost float64) error {
+	if math.IsNaN(cost) || cost < 0 {
+		return fmt.Errorf("invalid cost %v of the %s %q in the cost model (expected a non-negative number)", cost, kind, name)
+	}
+	return nil
+}
+
+// SetCostModel weights the edges of the graph with the given cost model
+// NOTE: to call before the analyses (see Process)
+func (g *MystGraph) SetCostModel(model CostModel) {
+	g.CostModel = model
+
+	g.Graph = multi.NewWeightedDirectedGraph()
+	for id := range g.IdNameMap {
+		g.Graph.AddNode(multi.Node(id))
+	}
+
+	for _, edge := range g.Metadata.Edges {
+		if edge.IsOfType(common.Disabled) {
+			continue
+		}
+
+		g.Graph.SetWeightedLine(multi.WeightedLine{
+			F: multi.Node(edge.Source.GraphID),
+			T: multi.Node(edge.Target.GraphID),
+			W: model.Cost(edge),
+		})
+	}
+
+	// the path analyzer caches the weighted view of the graph
+	g.pathAnalyzer = newPathAnalyzer(g)
+}
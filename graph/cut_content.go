@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"encoding/json"
+	"io"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// CutContentTag tags the edges and nodes restored by the cut-content mode
+// (see CutContentMetadata)
+const CutContentTag = "CutContent"
+
+// CutContentMetadata returns a copy of the metadata where the disabled edges (commented-out
+// links) are enabled and the virtual nodes (links to non-existent cards) are treated as real
+// ones, both tagged with CutContentTag: the game as it would have been if these links had shipped
+// NOTE: the attributes computed by the analyses (e.g., IsSink) are removed, to be computed again
+func CutContentMetadata(metadata *common.Metadata) *common.Metadata {
+	clone := metadata.Clone()
+
+	derived := []common.NodeAttribute{
+		common.IsIsolated, common.IsSource, common.IsSink,
+		common.IsUnreachable, common.IsSoftLock, common.IsCutOnly,
+	}
+	for _, node := range clone.Nodes {
+		node.Attributes = slices.DeleteFunc(node.Attributes, func(attr common.NodeAttribute) bool {
+			return slices.Contains(derived, attr)
+		})
+
+		if node.IsOfType(common.IsVirtual) {
+			node.Attributes = slices.DeleteFunc(node.Attributes, func(attr common.NodeAttribute) bool {
+				return attr == common.IsVirtual
+			})
+			node.Tags = append(node.Tags, CutContentTag)
+		}
+	}
+
+	for _, edge := range clone.Edges {
+		if edge.IsOfType(common.Disabled) || edge.IsOfType(common.NotImplemented) {
+			edge.Attributes = slices.DeleteFunc(edge.Attributes, func(attr common.EdgeAttribute) bool {
+				return attr == common.Disabled || attr == common.NotImplemented
+			})
+			edge.Tags = append(edge.Tags, CutContentTag)
+		}
+	}
+
+	return clone
+}
+
+// CutContentReport compares the analyses of the graph with those of its cut-content version
+type CutContentReport struct {
+	EnabledEdges   []common.EdgeInfo `json:"enabledEdges"`   // disabled edges enabled
+	RealizedNodes  []common.NodeInfo `json:"realizedNodes"`  // virtual nodes treated as real ones
+	NewlyReachable []common.NodeInfo `json:"newlyReachable"` // nodes reachable from the entry card only with the cut content
+
+	OriginalConnectedComponents   int `json:"originalConnectedComponents"`
+	CutContentConnectedComponents int `json:"cutContentConnectedComponents"`
+	OriginalSCCs                  int `json:"originalSCCs"`
+	CutContentSCCs                int `json:"cutContentSCCs"`
+	OriginalTraps                 int `json:"originalTraps"`
+	CutContentTraps               int `json:"cutContentTraps"`
+
+	OriginalMostSeparatedNodes   common.NodePairInfo `json:"originalMostSeparatedNodes"`
+	CutContentMostSeparatedNodes common.NodePairInfo `json:"cutContentMostSeparatedNodes"`
+}
+
+// WriteJSON writes the report as JSON
+func (r CutContentReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// CompareCutContent compares the processed graph with its processed cut-content version
+// (see CutContentMetadata)
+func CompareCutContent(original, cutContent *MystGraph) CutContentReport {
+	originalStats, cutStats := original.Metadata.Stats, cutContent.Metadata.Stats
+
+	report := CutContentReport{
+		OriginalConnectedComponents:   len(originalStats.ConnectedComponents),
+		CutContentConnectedComponents: len(cutStats.ConnectedComponents),
+		OriginalSCCs:                  len(originalStats.StronglyConnectedComponents),
+		CutContentSCCs:                len(cutStats.StronglyConnectedComponents),
+		OriginalTraps:                 len(original.GetTrapComponents()),
+		CutContentTraps:               len(cutContent.GetTrapComponents()),
+		OriginalMostSeparatedNodes:    originalStats.MostSeparatedNodes,
+		CutContentMostSeparatedNodes:  cutStats.MostSeparatedNodes,
+	}
+
+	for _, node := range original.Metadata.Nodes {
+		if node.IsOfType(common.IsVirtual) {
+			report.RealizedNodes = append(report.RealizedNodes, original.nodeInfo(node.GraphID))
+		}
+	}
+	sortNodeInfos(report.RealizedNodes)
+
+	for _, edge := range original.Metadata.Edges {
+		if edge.IsOfType(common.Disabled) {
+			report.EnabledEdges = append(report.EnabledEdges, common.EdgeInfo{
+				From: original.nodeInfo(edge.Source.GraphID),
+				To:   original.nodeInfo(edge.Target.GraphID),
+			})
+		}
+	}
+
+	entryID, err := original.EntryNodeID()
+	if err != nil {
+		return report
+	}
+
+	originalReachable := original.reachableCards(entryID)
+	for id := range cutContent.reachableCards(entryID) {
+		if !originalReachable[id] {
+			report.NewlyReachable = append(report.NewlyReachable, cutContent.nodeInfo(id))
+		}
+	}
+	sortNodeInfos(report.NewlyReachable)
+
+	return report
+}
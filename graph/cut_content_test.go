@@ -0,0 +1,99 @@
+package graph
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+func TestCutContent(t *testing.T) {
+	f := newFixture()
+	f.path("Myst:1", "Myst:2", "Myst:1")
+	f.link("Myst:2", "Myst:3", common.Disabled)
+	f.path("Myst:3", "Myst:4", "Myst:3")
+	f.card("Myst:4", common.IsSink)
+	f.card("Myst:Ghost", common.IsVirtual)
+	f.link("Myst:2", "Myst:Ghost", common.NotImplemented)
+	f.path("Channelwood:10", "Channelwood:11")
+
+	original := f.graph(t, "Myst:1")
+	original.CycleOptions = DefaultCycleOptions()
+	original.Process(original.Profile)
+
+	metadata := CutContentMetadata(original.Metadata)
+
+	// the original metadata is left unchanged
+	if !f.card("Myst:Ghost").IsOfType(common.IsVirtual) || !f.metadata.Edges[2].IsOfType(common.Disabled) {
+		t.Errorf("the original metadata was modified")
+	}
+
+	for _, node := range metadata.Nodes {
+		if node.IsOfType(common.IsVirtual) || node.IsOfType(common.IsSink) {
+			t.Errorf("%s: got the attributes %v", node.Name, node.Attributes)
+		}
+		if tagged := node.HasTag(CutContentTag); tagged != (node.Name == "Myst:Ghost") {
+			t.Errorf("%s: got the tags %q", node.Name, node.Tags)
+		}
+	}
+	for _, edge := range metadata.Edges {
+		if edge.IsOfType(common.Disabled) || edge.IsOfType(common.NotImplemented) {
+			t.Errorf("%s -> %s: got the attributes %v", edge.Source.Name, edge.Target.Name, edge.Attributes)
+		}
+		restored := edge.Source.Name == "Myst:2" && (edge.Target.Name == "Myst:3" || edge.Target.Name == "Myst:Ghost")
+		if edge.HasTag(CutContentTag) != restored {
+			t.Errorf("%s -> %s: got the tags %q", edge.Source.Name, edge.Target.Name, edge.Tags)
+		}
+	}
+
+	cutContent, err := NewGraph(metadata)
+	if err != nil {
+		t.Fatalf("unable to instantiate the cut-content graph: %v", err)
+	}
+	cutContent.CycleOptions = DefaultCycleOptions()
+	cutContent.Process(original.Profile)
+
+	report := CompareCutContent(original, cutContent)
+
+	nodeNames := func(nodes []common.NodeInfo) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Name)
+		}
+		return result
+	}
+
+	if len(report.EnabledEdges) != 1 || report.EnabledEdges[0].From.Name != "Myst:2" || report.EnabledEdges[0].To.Name != "Myst:3" {
+		t.Errorf("got the enabled edges %+v, expected Myst:2 -> Myst:3", report.EnabledEdges)
+	}
+	if realized := nodeNames(report.RealizedNodes); !slices.Equal(realized, []string{"Myst:Ghost"}) {
+		t.Errorf("got the realized nodes %q", realized)
+	}
+	if reachable := nodeNames(report.NewlyReachable); !slices.Equal(reachable, []string{"Myst:3", "Myst:4"}) {
+		t.Errorf("got the newly reachable nodes %q", reachable)
+	}
+
+	counts := [][2]int{
+		{report.OriginalConnectedComponents, report.CutContentConnectedComponents},
+		{report.OriginalSCCs, report.CutContentSCCs},
+		// Myst:3 and Myst:4 become a trap once linked from Myst:2
+		{report.OriginalTraps, report.CutContentTraps},
+	}
+	if expected := [][2]int{{3, 2}, {5, 5}, {2, 3}}; !slices.Equal(counts, expected) {
+		t.Errorf("got the components, SCCs, and traps %v, expected %v", counts, expected)
+	}
+
+	for _, test := range []struct {
+		pair             common.NodePairInfo
+		source, target   string
+		expectedDistance float64
+	}{
+		{report.OriginalMostSeparatedNodes, "Myst:1", "Myst:Ghost", 2},
+		{report.CutContentMostSeparatedNodes, "Myst:1", "Myst:4", 3},
+	} {
+		if test.pair.Source.Name != test.source || test.pair.Target.Name != test.target || test.pair.Distance != test.expectedDistance {
+			t.Errorf("got the most separated nodes %s -> %s (%v), expected %s -> %s (%v)",
+				test.pair.Source.Name, test.pair.Target.Name, test.pair.Distance, test.source, test.target, test.expectedDistance)
+		}
+	}
+}
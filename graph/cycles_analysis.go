@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// CycleOptions bounds the enumeration of the elementary cycles
+type CycleOptions struct {
+	// MaxLength is the maximum number of nodes of a cycle
+	MaxLength int
+	// Limit is the maximum number of cycles (no limit if <= 0)
+	Limit int
+	// CorridorMaxLength is the maximum number of nodes of a corridor loop
+	CorridorMaxLength int
+}
+
+func DefaultCycleOptions() CycleOptions {
+	return CycleOptions{
+		MaxLength:         8,
+		Limit:             100000,
+		CorridorMaxLength: 6,
+	}
+}
+
+// rotationLoopLength is the number of views of a turn-in-place
+const rotationLoopLength = 4
+
+// FindCycles enumerates the elementary cycles of the graph, up to the configured length,
+// and classifies them; it reports whether the enumeration stopped at the configured limit
+// NOTE: the enumeration follows the bounded variant of Johnson's algorithm by Gupta and Suzumura
+// (cycles rooted at their lowest node, searched in the strongly connected components only):
+// a node is locked at its position in the path, and unlocked (with its predecessors) down to
+// the length of the shortest return to the start found; the self-loops
+// are reported by FindNodesWithSelfLoops, and the backtracking and disabled edges are ignored
+func (g *MystGraph) FindCycles() ([]common.CycleInfo, bool) {
+	options := g.CycleOptions
+	view := g.traversableGraph()
+
+	// the nodes of the same strongly connected component (the only ones sharing cycles)
+	componentOf := make(map[int64]int)
+	for i, scc := range g.Metadata.Stats.StronglyConnectedComponents {
+		for _, id := range scc.Nodes {
+			componentOf[id] = i
+		}
+	}
+
+	successors := make(map[int64][]int64)
+	nodeIDs := g.traverser.getAllNodeIDs()
+	for _, id := range nodeIDs {
+		from := view.From(id)
+		for from.Next() {
+			if next := from.Node().ID(); componentOf[next] == componentOf[id] {
+				successors[id] = append(successors[id], next)
+			}
+		}
+		slices.Sort(successors[id])
+	}
+
+	var cycles []common.CycleInfo
+	truncated := false
+
+	for _, start := range nodeIDs {
+		if truncated {
+			break
+		}
+
+		path := []int64{start}
+		onPath := map[int64]bool{start: true}
+
+		// a node can only be appended to the path before its lock (the maximum length by default)
+		lock := map[int64]int{start: 0}
+		lockOf := func(id int64) int {
+			if position, ok := lock[id]; ok {
+				return position
+			}
+			return options.MaxLength
+		}
+
+		// the predecessors to unlock when a shorter return to the start is found through a node
+		blocked := make(map[int64][]int64)
+
+		// relax unlocks a node (and its blocked predecessors) returning to the start in the given distance
+		var relax func(id int64, distance int)
+		relax = func(id int64, distance int) {
+			position := options.MaxLength - distance + 1
+			if lockOf(id) >= position {
+				return
+			}
+
+			lock[id] = position
+			for _, previous := range blocked[id] {
+				if !onPath[previous] {
+					relax(previous, distance+1)
+				}
+			}
+		}
+
+		// search returns the distance of the shortest return to the start (the maximum length if none)
+		var search func(current int64) int
+		search = func(current int64) int {
+			distance := options.MaxLength
+			for _, next := range successors[current] {
+				if truncated {
+					return distance
+				}
+
+				switch {
+				case next == start:
+					if options.Limit > 0 && len(cycles) >= options.Limit {
+						truncated = true
+						return distance
+					}
+					cycles = append(cycles, g.newCycleInfo(slices.Clone(path), options))
+					distance = 1
+				case next > start && !onPath[next] && len(path) < lockOf(next):
+					lock[next] = len(path)
+					path = append(path, next)
+					onPath[next] = true
+					distance = min(distance, search(next)+1)
+					onPath[next] = false
+					path = path[:len(path)-1]
+				}
+			}
+
+			// NOTE: the successful searches are also registered, since a successor on the path
+			// (or locked) may later offer a shorter return
+			for _, next := range successors[current] {
+				if !slices.Contains(blocked[next], current) {
+					blocked[next] = append(blocked[next], current)
+				}
+			}
+			if distance < options.MaxLength {
+				relax(current, distance)
+			}
+
+			return distance
+		}
+
+		search(start)
+	}
+
+	return cycles, truncated
+}
+
+// newCycleInfo classifies a cycle and gathers its stacks and edge attributes
+func (g *MystGraph) newCycleInfo(nodes []int64, options CycleOptions) common.CycleInfo {
+	cycle := common.CycleInfo{
+		Nodes:  nodes,
+		Stacks: g.componentStacks(nodes),
+	}
+
+	for i, from := range nodes {
+		to := nodes[(i+1)%len(nodes)]
+
+		edges, _ := g.GetAllEdges(from, to)
+		for _, edge := range edges {
+			for _, attr := range edge.Attributes {
+				if !slices.Contains(cycle.EdgeAttributes, attr) {
+					cycle.EdgeAttributes = append(cycle.EdgeAttributes, attr)
+				}
+			}
+		}
+	}
+	slices.Sort(cycle.EdgeAttributes)
+
+	singleStack := len(cycle.Stacks) == 1
+	switch {
+	case singleStack && len(nodes) == rotationLoopLength && g.isReversibleCycle(nodes):
+		cycle.Kind = common.RotationLoop
+	case singleStack && len(nodes) <= options.CorridorMaxLength:
+		cycle.Kind = common.CorridorLoop
+	default:
+		cycle.Kind = common.ExplorationLoop
+	}
+
+	return cycle
+}
+
+// CountCyclesByKind returns the number of cycles of each kind
+func CountCyclesByKind(cycles []common.CycleInfo) map[common.CycleKind]int {
+	counts := make(map[common.CycleKind]int)
+	for _, cycle := range cycles {
+		counts[cycle.Kind]++
+	}
+	return counts
+}
+
+// cycleRecord is the exported representation of a cycle
+type cycleRecord struct {
+	Kind           string   `json:"kind"`
+	Length         int      `json:"length"`
+	Stacks         []string `json:"stacks"`
+	EdgeAttributes []string `json:"edgeAttributes"`
+	Nodes          []string `json:"nodes"`
+	NodeIDs        []int64  `json:"nodeIds"`
+}
+
+// WriteCycleCatalog exports the cycle catalog in the given format (`json` or `csv`)
+func (g *MystGraph) WriteCycleCatalog(w io.Writer, format string) error {
+	records := make([]cycleRecord, 0, len(g.Metadata.Stats.Cycles))
+	for _, cycle := range g.Metadata.Stats.Cycles {
+		record := cycleRecord{
+			Kind:    string(cycle.Kind),
+			Length:  len(cycle.Nodes),
+			Stacks:  cycle.Stacks,
+			Nodes:   g.FormatPathAsNames(cycle.Nodes),
+			NodeIDs: cycle.Nodes,
+		}
+		for _, attr := range cycle.EdgeAttributes {
+			record.EdgeAttributes = append(record.EdgeAttributes, attr.String())
+		}
+		records = append(records, record)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Kind < records[j].Kind
+	})
+
+	switch strings.ToLower(format) {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(records)
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"kind", "length", "stacks", "edge_attributes", "nodes"}); err != nil {
+			return err
+		}
+		for _, record := range records {
+			if err := writer.Write([]string{
+				record.Kind,
+				strconv.Itoa(record.Length),
+				strings.Join(record.Stacks, "|"),
+				strings.Join(record.EdgeAttributes, "|"),
+				strings.Join(record.Nodes, " -> "),
+			}); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unknown cycle catalog format %q (expected json or csv)", format)
+	}
+}
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// findCycles enumerates the cycles of the graph with the given options
+// (FindCycles relies on the strongly connected components)
+func findCycles(g *MystGraph, options CycleOptions) ([]common.CycleInfo, bool) {
+	g.CycleOptions = options
+	g.Metadata.Stats.StronglyConnectedComponents, _ = g.FindStronglyConnectedComponents()
+	return g.FindCycles()
+}
+
+// bruteForceCycles enumerates the elementary cycles of the traversable view up to the given length,
+// as sorted keys (nodes starting with the lowest ID)
+func bruteForceCycles(g *MystGraph, maxLength int) []string {
+	view := g.traversableGraph()
+
+	var cycles []string
+	var extend func(start int64, path []int64)
+	extend = func(start int64, path []int64) {
+		successors := view.From(path[len(path)-1])
+		for successors.Next() {
+			next := successors.Node().ID()
+			switch {
+			case next == start:
+				cycles = append(cycles, fmt.Sprint(path))
+			case next > start && !slices.Contains(path, next) && len(path) < maxLength:
+				extend(start, append(slices.Clone(path), next))
+			}
+		}
+	}
+
+	for _, id := range g.traverser.getAllNodeIDs() {
+		extend(id, []int64{id})
+	}
+
+	slices.Sort(cycles)
+	return cycles
+}
+
+func TestFindCyclesBruteForce(t *testing.T) {
+	for seed := range int64(50) {
+		g := randomFixture(seed, 12, 0.2).graph(t, "")
+
+		for maxLength := 2; maxLength <= 8; maxLength++ {
+			cycles, truncated := findCycles(g, CycleOptions{MaxLength: maxLength, CorridorMaxLength: 6})
+			if truncated {
+				t.Fatalf("seed %d, length %d: truncated without limit", seed, maxLength)
+			}
+
+			keys := make([]string, len(cycles))
+			for i, cycle := range cycles {
+				keys[i] = fmt.Sprint(cycle.Nodes)
+			}
+			slices.Sort(keys)
+
+			if expected := bruteForceCycles(g, maxLength); !slices.Equal(keys, expected) {
+				t.Errorf("seed %d, length %d: got %d cycles, expected %d", seed, maxLength, len(keys), len(expected))
+			}
+		}
+	}
+}
+
+func TestFindCyclesLimit(t *testing.T) {
+	g := randomFixture(1, 12, 0.3).graph(t, "")
+
+	all, truncated := findCycles(g, CycleOptions{MaxLength: 5, CorridorMaxLength: 6})
+	if truncated || len(all) < 10 {
+		t.Fatalf("got %d cycles (truncated: %v), expected at least 10", len(all), truncated)
+	}
+
+	tests := []struct {
+		limit     int
+		count     int
+		truncated bool
+	}{
+		{1, 1, true},
+		{10, 10, true},
+		{len(all) - 1, len(all) - 1, true},
+		{len(all), len(all), false}, // no cycle beyond the limit
+		{len(all) + 1, len(all), false},
+	}
+
+	for _, test := range tests {
+		cycles, truncated := findCycles(g, CycleOptions{MaxLength: 5, Limit: test.limit, CorridorMaxLength: 6})
+		if len(cycles) != test.count || truncated != test.truncated {
+			t.Errorf("limit %d: got %d cycles (truncated: %v), expected %d (truncated: %v)", test.limit, len(cycles), truncated, test.count, test.truncated)
+		}
+
+		// the first cycles of the whole enumeration
+		for i := range cycles {
+			if !slices.Equal(cycles[i].Nodes, all[i].Nodes) {
+				t.Errorf("limit %d: cycle %d differs", test.limit, i)
+				break
+			}
+		}
+	}
+}
+
+func TestCycleKinds(t *testing.T) {
+	f := newFixture()
+
+	// a turn-in-place (both directions), a one-way corridor, a one-way long loop, and a loop
+	// through another Age
+	f.path("Myst:1", "Myst:2", "Myst:3", "Myst:4", "Myst:1")
+	f.path("Myst:1", "Myst:4", "Myst:3", "Myst:2", "Myst:1")
+	f.path("Myst:10", "Myst:11", "Myst:12", "Myst:10")
+	f.path("Myst:20", "Myst:21", "Myst:22", "Myst:23", "Myst:24", "Myst:25", "Myst:26", "Myst:20")
+	f.path("Myst:30", "Channelwood:31", "Myst:30")
+	g := f.graph(t, "")
+
+	cycles, _ := findCycles(g, DefaultCycleOptions())
+
+	var kinds []string
+	for _, cycle := range cycles {
+		kinds = append(kinds, fmt.Sprintf("%s: %s", names(g, cycle.Nodes), cycle.Kind))
+	}
+	slices.Sort(kinds)
+
+	// the reversible edges of the turn-in-place are corridors of two cards
+	expected := []string{
+		"Myst:1 Myst:2 Myst:3 Myst:4: rotation",
+		"Myst:1 Myst:2: corridor",
+		"Myst:1 Myst:4 Myst:3 Myst:2: rotation",
+		"Myst:1 Myst:4: corridor",
+		"Myst:10 Myst:11 Myst:12: corridor",
+		"Myst:2 Myst:3: corridor",
+		"Myst:20 Myst:21 Myst:22 Myst:23 Myst:24 Myst:25 Myst:26: exploration",
+		"Myst:3 Myst:4: corridor",
+		"Myst:30 Channelwood:31: exploration",
+	}
+	if !slices.Equal(kinds, expected) {
+		t.Errorf("got:\n%s\nexpected:\n%s", strings.Join(kinds, "\n"), strings.Join(expected, "\n"))
+	}
+}
@@ -0,0 +1,199 @@
+package graph
+
+import (
+	"cmp"
+	"container/heap"
+	"math"
+	"runtime"
+	"slices"
+	"sync"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// arc is an edge of the state graph, between state indexes
+type arc struct {
+	to     int32
+	weight float64
+}
+
+// distanceMatrix returns the shortest paths between all pairs of nodes, computed on first use
+// and shared by the path analyses
+func (pa *pathAnalyzer) distanceMatrix() *common.DistanceMatrix {
+	if pa.distances == nil {
+		pa.distances = pa.computeDistanceMatrix()
+	}
+	return pa.distances
+}
+
+// computeDistanceMatrix calculates the shortest paths from every node of the state graph
+// (see weightedGraph): breadth-first searches if all the edges cost the same (e.g., clicks),
+// Dijkstra otherwise, the source nodes being sharded across goroutines
+func (pa *pathAnalyzer) computeDistanceMatrix() *common.DistanceMatrix {
+	view := pa.weightedGraph()
+	nodeIDs := pa.g.traverser.getAllNodeIDs()
+	states, adjacency, unitWeights := stateAdjacency(view, nodeIDs)
+
+	matrix := &common.DistanceMatrix{
+		Nodes:        nodeIDs,
+		States:       make([]int64, len(states)),
+		Distances:    make([]float64, len(nodeIDs)*len(states)),
+		Predecessors: make([]int32, len(nodeIDs)*len(states)),
+	}
+	for i, id := range states {
+		matrix.States[i] = view.card(id)
+	}
+
+	workers := min(runtime.GOMAXPROCS(0), len(nodeIDs))
+
+	var wg sync.WaitGroup
+	for worker := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			// NOTE: each row is written by a single goroutine
+			for row := worker; row < len(nodeIDs); row += workers {
+				offset := row * len(states)
+				distances := matrix.Distances[offset : offset+len(states)]
+				predecessors := matrix.Predecessors[offset : offset+len(states)]
+
+				if unitWeights {
+					breadthFirstDistances(adjacency, int32(row), distances, predecessors)
+				} else {
+					dijkstraDistances(adjacency, int32(row), distances, predecessors)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	return matrix
+}
+
+// stateAdjacency returns the states of the state graph (the nodes first, in the given order,
+// then the pending states), and its adjacency lists between state indexes
+// NOTE: unitWeights reports whether all the edges have a weight of 1
+func stateAdjacency(view *stateGraph, nodeIDs []int64) (states []int64, adjacency [][]arc, unitWeights bool) {
+	// states: the nodes first, then the pending states (transitive cards passed through)
+	states = slices.Clone(nodeIDs)
+	pendingIDs := make([]int64, 0, len(view.cards))
+	for id := range view.cards {
+		pendingIDs = append(pendingIDs, id)
+	}
+	slices.Sort(pendingIDs)
+	states = append(states, pendingIDs...)
+
+	stateIndex := make(map[int64]int32, len(states))
+	for i, id := range states {
+		stateIndex[id] = int32(i)
+	}
+
+	// adjacency lists (sorted, so that the predecessors are deterministic)
+	adjacency = make([][]arc, len(states))
+	unitWeights = true
+	for i, id := range states {
+		successors := view.From(id)
+		for successors.Next() {
+			toID := successors.Node().ID()
+			to, exists := stateIndex[toID]
+			if !exists {
+				continue
+			}
+
+			weight := view.WeightedEdge(id, toID).Weight()
+			if weight != 1 {
+				unitWeights = false
+			}
+			adjacency[i] = append(adjacency[i], arc{to: to, weight: weight})
+		}
+
+		slices.SortFunc(adjacency[i], func(a, b arc) int {
+			return cmp.Compare(a.to, b.to)
+		})
+	}
+
+	return states, adjacency, unitWeights
+}
+
+// resetDistances sets the distances of a row to +Inf (0 for the source),
+// and its predecessors to -1
+func resetDistances(source int32, distances []float64, predecessors []int32) {
+	for i := range distances {
+		distances[i] = math.Inf(1)
+		predecessors[i] = -1
+	}
+	distances[source] = 0
+}
+
+// breadthFirstDistances calculates the distances from the source (edges of weight 1)
+func breadthFirstDistances(adjacency [][]arc, source int32, distances []float64, predecessors []int32) {
+	resetDistances(source, distances, predecessors)
+
+	queue := []int32{source}
+	for len(queue) > 0 {
+		state := queue[0]
+		queue = queue[1:]
+
+		for _, a := range adjacency[state] {
+			if !math.IsInf(distances[a.to], 1) {
+				continue
+			}
+
+			distances[a.to] = distances[state] + 1
+			predecessors[a.to] = state
+			queue = append(queue, a.to)
+		}
+	}
+}
+
+// dijkstraDistances calculates the distances from the source (weighted edges)
+func dijkstraDistances(adjacency [][]arc, source int32, distances []float64, predecessors []int32) {
+	resetDistances(source, distances, predecessors)
+
+	queue := &stateQueue{{state: source}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(stateQueueItem)
+		if item.distance > distances[item.state] {
+			continue // outdated item
+		}
+
+		for _, a := range adjacency[item.state] {
+			distance := item.distance + a.weight
+			if distance < distances[a.to] {
+				distances[a.to] = distance
+				predecessors[a.to] = item.state
+				heap.Push(queue, stateQueueItem{state: a.to, distance: distance})
+			}
+		}
+	}
+}
+
+// stateQueueItem is a state of the Dijkstra priority queue
+type stateQueueItem struct {
+	state    int32
+	distance float64
+}
+
+// stateQueue is the Dijkstra priority queue (ties broken by state index, for determinism)
+type stateQueue []stateQueueItem
+
+func (q stateQueue) Len() int { return len(q) }
+
+func (q stateQueue) Less(i, j int) bool {
+	if q[i].distance != q[j].distance {
+		return q[i].distance < q[j].distance
+	}
+	return q[i].state < q[j].state
+}
+
+func (q stateQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *stateQueue) Push(item any) { *q = append(*q, item.(stateQueueItem)) }
+
+func (q *stateQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
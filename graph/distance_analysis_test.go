@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+
+	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// randomAdjacency returns a random graph, as adjacency lists and as a gonum graph
+func randomAdjacency(seed int64, n int, density float64, unitWeights bool) ([][]arc, *simple.WeightedDirectedGraph) {
+	r := rand.New(rand.NewSource(seed))
+
+	adjacency := make([][]arc, n)
+	reference := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	for i := range n {
+		reference.AddNode(simple.Node(i))
+	}
+
+	for from := range n {
+		for to := range n {
+			if from == to || r.Float64() >= density {
+				continue
+			}
+
+			weight := 1.0
+			if !unitWeights {
+				// NOTE: the zero weights are allowed by the cost model (e.g., age-switches)
+				weight = float64(r.Intn(5)) + r.Float64()*float64(r.Intn(2))
+			}
+			adjacency[from] = append(adjacency[from], arc{to: int32(to), weight: weight})
+			reference.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(from), T: simple.Node(to), W: weight})
+		}
+	}
+
+	return adjacency, reference
+}
+
+func TestShortestDistances(t *testing.T) {
+	tests := []struct {
+		name        string
+		nodes       int
+		density     float64
+		unitWeights bool
+	}{
+		{"single node", 1, 0, true},
+		{"no edges", 5, 0, true},
+		{"sparse, unit weights", 30, 0.05, true},
+		{"dense, unit weights", 30, 0.5, true},
+		{"sparse, weighted", 30, 0.05, false},
+		{"dense, weighted", 30, 0.5, false},
+		{"large, weighted", 200, 0.02, false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for seed := range int64(5) {
+				adjacency, reference := randomAdjacency(seed, test.nodes, test.density, test.unitWeights)
+
+				distances := make([]float64, test.nodes)
+				predecessors := make([]int32, test.nodes)
+
+				for source := range int32(test.nodes) {
+					if test.unitWeights {
+						breadthFirstDistances(adjacency, source, distances, predecessors)
+					} else {
+						dijkstraDistances(adjacency, source, distances, predecessors)
+					}
+
+					expected := path.DijkstraFrom(simple.Node(source), reference)
+					for to := range int32(test.nodes) {
+						if expectedDistance := expected.WeightTo(int64(to)); math.Abs(distances[to]-expectedDistance) > 1e-9 {
+							t.Fatalf("seed %d: distance from %d to %d is %v, expected %v", seed, source, to, distances[to], expectedDistance)
+						}
+
+						// the predecessors lead back to the source on a shortest path
+						switch {
+						case to == source || math.IsInf(distances[to], 1):
+							if predecessors[to] != -1 {
+								t.Fatalf("seed %d: node %d from %d has the predecessor %d", seed, to, source, predecessors[to])
+							}
+						default:
+							previous := predecessors[to]
+							if previous < 0 {
+								t.Fatalf("seed %d: node %d from %d has no predecessor", seed, to, source)
+							}
+							if weight := reference.WeightedEdge(int64(previous), int64(to)).Weight(); math.Abs(distances[previous]+weight-distances[to]) > 1e-9 {
+								t.Fatalf("seed %d: predecessor %d of %d from %d is not on a shortest path", seed, previous, to, source)
+							}
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+// randomFixture returns a random graph of three stacks, with disabled and restrictive transitivity edges
+func randomFixture(seed int64, n int, density float64) *fixture {
+	r := rand.New(rand.NewSource(seed))
+	stacks := []string{"Myst", "Channelwood", "Selenitic"}
+
+	f := newFixture()
+	cards := make([]string, n)
+	for i := range n {
+		cards[i] = fmt.Sprintf("%s:%d", stacks[i%len(stacks)], 1000+i)
+		f.card(cards[i])
+	}
+
+	for _, from := range cards {
+		for _, to := range cards {
+			switch draw := r.Float64(); {
+			case from == to || draw >= density:
+			case draw < density/10:
+				f.link(from, to, common.Disabled)
+			case draw < density/5:
+				f.transitive(from, to, cards[r.Intn(n)], int64(r.Intn(3)))
+			default:
+				f.link(from, to)
+			}
+		}
+	}
+	return f
+}
+
+func TestDistanceMatrixSharding(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(0))
+
+	for _, preset := range []string{ClicksCostPreset, AgeSwitchesCostPreset} {
+		t.Run(preset, func(t *testing.T) {
+			f := randomFixture(1, 60, 0.08)
+			g := f.graph(t, "")
+
+			model, err := ParseCostPreset(preset)
+			if err != nil {
+				t.Fatal(err)
+			}
+			g.SetCostModel(model)
+
+			runtime.GOMAXPROCS(1)
+			expected := g.pathAnalyzer.computeDistanceMatrix()
+
+			for _, shards := range []int{2, 3, 7, 16} {
+				runtime.GOMAXPROCS(shards)
+				matrix := g.pathAnalyzer.computeDistanceMatrix()
+
+				if !slices.Equal(matrix.Nodes, expected.Nodes) || !slices.Equal(matrix.States, expected.States) {
+					t.Fatalf("%d shards: the nodes or states differ", shards)
+				}
+				if !slices.Equal(matrix.Distances, expected.Distances) {
+					t.Errorf("%d shards: the distances differ", shards)
+				}
+				if !slices.Equal(matrix.Predecessors, expected.Predecessors) {
+					t.Errorf("%d shards: the predecessors differ", shards)
+				}
+			}
+		})
+	}
+}
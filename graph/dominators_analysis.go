@@ -0,0 +1,164 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
+
+	"gonum.org/v1/gonum/graph/flow"
+)
+
+// DominatorTree is the dominator tree of the graph rooted at an entry node:
+// a node d dominates a node n if every path from the entry to n passes through d
+// (the cards every player must visit before reaching n)
+type DominatorTree struct {
+	Root int64
+
+	idom       map[int64]int64   // node -> immediate dominator (the root has none)
+	dominators map[int64][]int64 // node -> dominators, from the root to the immediate dominator
+	children   map[int64][]int64 // node -> nodes it immediately dominates
+	frontiers  map[int64][]int64 // node -> dominance frontier
+}
+
+// ComputeDominators computes the dominator tree (Lengauer–Tarjan) rooted at the entry node
+// NOTE: the dominators are computed on the state graph (see transitivityGraph), so that the paths
+// through a transitive card follow the Head edge paired with the Tail edge, and the disabled and
+// untraversable (e.g., backtracking) edges are skipped; the nodes unreachable from the entry are
+// not in the tree. A card dominates the nodes that one of its states dominates (the transitive
+// cards whose states only dominate a node together are not reported), and its immediate dominator
+// is the card of the nearest of these states: a card passed through several times is ordered by
+// its last pass
+func (g *MystGraph) ComputeDominators(entryID int64) (*DominatorTree, error) {
+	if g.Graph.Node(entryID) == nil {
+		return nil, fmt.Errorf("entry node %d: %w", entryID, common.NodeNotFoundErr)
+	}
+
+	view := g.transitivityGraph()
+	visits := view.addVisitStates()
+	lt := flow.Dominators(view.Node(entryID), view)
+
+	tree := &DominatorTree{
+		Root:       entryID,
+		idom:       make(map[int64]int64),
+		dominators: make(map[int64][]int64),
+		children:   make(map[int64][]int64),
+		frontiers:  make(map[int64][]int64),
+	}
+
+	for _, id := range g.traverser.getAllNodeIDs() {
+		if id == entryID {
+			continue
+		}
+
+		// the states dominating every state of the node (see addVisitStates), from the nearest one
+		visit, transitive := visits[id]
+		if !transitive {
+			visit = id
+		}
+
+		var dominators []int64
+		for dominator := lt.DominatorOf(visit); dominator != nil; dominator = lt.DominatorOf(dominator.ID()) {
+			if card := view.card(dominator.ID()); card != id && !slices.Contains(dominators, card) {
+				dominators = append(dominators, card)
+			}
+		}
+		if len(dominators) == 0 {
+			continue // unreachable from the entry
+		}
+
+		idom := dominators[0]
+		slices.Reverse(dominators)
+
+		tree.idom[id] = idom
+		tree.dominators[id] = dominators
+		tree.children[idom] = append(tree.children[idom], id)
+	}
+
+	// predecessors of the nodes in the tree, through any of their states
+	predecessors := make(map[int64][]int64)
+	edges := view.Edges()
+	for edges.Next() {
+		edge := edges.Edge()
+		from, to := view.card(edge.From().ID()), view.card(edge.To().ID())
+		if from != to && tree.Contains(from) && tree.Contains(to) && !slices.Contains(predecessors[to], from) {
+			predecessors[to] = append(predecessors[to], from)
+		}
+	}
+
+	// dominance frontiers (Cooper, Harvey, and Kennedy): a join node belongs to the frontier of
+	// its predecessors and of their dominators, except the ones strictly dominating it
+	// NOTE: the dominators of the predecessors are not all ancestors in the tree (a card passed
+	// through several times), hence the walk on the dominator lists
+	for _, id := range g.traverser.getAllNodeIDs() {
+		if !tree.Contains(id) || len(predecessors[id]) < 2 {
+			continue
+		}
+
+		for _, predecessor := range predecessors[id] {
+			for _, runner := range append([]int64{predecessor}, tree.Dominators(predecessor)...) {
+				if runner != id && slices.Contains(tree.Dominators(id), runner) {
+					continue
+				}
+				if !slices.Contains(tree.frontiers[runner], id) {
+					tree.frontiers[runner] = append(tree.frontiers[runner], id)
+				}
+			}
+		}
+	}
+
+	for id := range tree.frontiers {
+		slices.Sort(tree.frontiers[id])
+	}
+
+	return tree, nil
+}
+
+// Contains reports whether the node is reachable from the root (hence in the tree)
+func (t *DominatorTree) Contains(id int64) bool {
+	_, ok := t.idom[id]
+	return ok || id == t.Root
+}
+
+// ImmediateDominator returns the immediate dominator of a node
+// (false for the root and for the unreachable nodes)
+func (t *DominatorTree) ImmediateDominator(id int64) (int64, bool) {
+	idom, ok := t.idom[id]
+	return idom, ok
+}
+
+// Dominators returns all the dominators of a node, from the root to its immediate dominator
+// (the mandatory chokepoints to reach it)
+func (t *DominatorTree) Dominators(id int64) []int64 {
+	return t.dominators[id]
+}
+
+// Children returns the sorted nodes immediately dominated by a node
+func (t *DominatorTree) Children(id int64) []int64 {
+	return t.children[id]
+}
+
+// DominanceFrontier returns the sorted dominance frontier of a node: the nodes where its
+// dominance ends (reachable from it, but also through other paths)
+func (t *DominatorTree) DominanceFrontier(id int64) []int64 {
+	return t.frontiers[id]
+}
+
+// Edges returns the edges of the tree (immediate dominator -> node), sorted by node ID
+func (t *DominatorTree) Edges() [][2]int64 {
+	edges := make([][2]int64, 0, len(t.idom))
+	for id, idom := range t.idom {
+		edges = append(edges, [2]int64{idom, id})
+	}
+
+	slices.SortFunc(edges, func(a, b [2]int64) int {
+		if a[1] < b[1] {
+			return -1
+		}
+		if a[1] > b[1] {
+			return 1
+		}
+		return 0
+	})
+	return edges
+}
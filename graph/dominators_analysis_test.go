@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestDominatorsTransitivity(t *testing.T) {
+	f, _ := crossedTransitivity()
+	f.path("Myst:41", "Myst:50")
+	f.path("Myst:20", "Myst:50")
+	g := f.graph(t, "Myst:1")
+
+	tree, err := g.ComputeDominators(f.id("Myst:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the paths through the transitive cards are only the ones of Myst:1 -> Myst:41:
+	// Myst:30 is only reached after Myst:2, and Myst:10 (passed through twice) is ordered
+	// by its last pass
+	tests := []struct {
+		card       string
+		idom       string
+		dominators string
+	}{
+		{"Myst:10", "Myst:1", "Myst:1"},
+		{"Myst:20", "Myst:10", "Myst:1 Myst:10"},
+		{"Myst:11", "Myst:20", "Myst:1 Myst:10 Myst:20"},
+		{"Myst:40", "Myst:11", "Myst:1 Myst:10 Myst:20 Myst:11"},
+		{"Myst:2", "Myst:40", "Myst:1 Myst:10 Myst:20 Myst:11 Myst:40"},
+		{"Myst:30", "Myst:10", "Myst:1 Myst:20 Myst:11 Myst:40 Myst:2 Myst:10"},
+		{"Myst:41", "Myst:11", "Myst:1 Myst:20 Myst:40 Myst:2 Myst:10 Myst:30 Myst:11"},
+		{"Myst:50", "Myst:20", "Myst:1 Myst:10 Myst:20"},
+	}
+
+	for _, test := range tests {
+		idom, ok := tree.ImmediateDominator(f.id(test.card))
+		if !ok || g.GetNameForID(idom) != test.idom {
+			t.Errorf("%s: got the immediate dominator %s, expected %s", test.card, g.GetNameForID(idom), test.idom)
+		}
+		if dominators := names(g, tree.Dominators(f.id(test.card))); dominators != test.dominators {
+			t.Errorf("%s: got the dominators %q, expected %q", test.card, dominators, test.dominators)
+		}
+	}
+
+	// the join cards: Myst:10 (reached from Myst:1 and Myst:2), Myst:11 (from Myst:20 and Myst:30),
+	// and Myst:50 (from Myst:20 and Myst:41)
+	frontiers := map[string]string{
+		"Myst:1":  "",
+		"Myst:10": "Myst:10",
+		"Myst:20": "Myst:10",
+		"Myst:11": "Myst:10 Myst:11 Myst:50",
+		"Myst:40": "Myst:10 Myst:11 Myst:50",
+		"Myst:2":  "Myst:10 Myst:11 Myst:50",
+		"Myst:30": "Myst:11 Myst:50",
+		"Myst:41": "Myst:50",
+		"Myst:50": "",
+	}
+	for card, expected := range frontiers {
+		if frontier := names(g, tree.DominanceFrontier(f.id(card))); frontier != expected {
+			t.Errorf("%s: got the dominance frontier %q, expected %q", card, frontier, expected)
+		}
+	}
+}
+
+func TestDominanceFrontiers(t *testing.T) {
+	f := newFixture()
+
+	// a branch (Myst:3 or Myst:4) inside a loop (Myst:2 to Myst:7), bypassed through Myst:5,
+	// and a card unreachable from the entry
+	f.path("Myst:1", "Myst:2", "Myst:3", "Myst:6", "Myst:7", "Myst:8")
+	f.path("Myst:2", "Myst:4", "Myst:6")
+	f.path("Myst:1", "Myst:5", "Myst:7", "Myst:2")
+	f.path("Myst:9", "Myst:2")
+	g := f.graph(t, "Myst:1")
+
+	tree, err := g.ComputeDominators(f.id("Myst:1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		card     string
+		idom     string
+		frontier string
+	}{
+		{"Myst:1", "", ""},
+		{"Myst:2", "Myst:1", "Myst:7"},
+		{"Myst:3", "Myst:2", "Myst:6"},
+		{"Myst:4", "Myst:2", "Myst:6"},
+		{"Myst:5", "Myst:1", "Myst:7"},
+		{"Myst:6", "Myst:2", "Myst:7"},
+		{"Myst:7", "Myst:1", "Myst:2"},
+		{"Myst:8", "Myst:7", ""},
+	}
+
+	for _, test := range tests {
+		idom, ok := tree.ImmediateDominator(f.id(test.card))
+		if ok != (test.idom != "") || (ok && g.GetNameForID(idom) != test.idom) {
+			t.Errorf("%s: got the immediate dominator %s (%v), expected %q", test.card, g.GetNameForID(idom), ok, test.idom)
+		}
+		if frontier := names(g, tree.DominanceFrontier(f.id(test.card))); frontier != test.frontier {
+			t.Errorf("%s: got the dominance frontier %q, expected %q", test.card, frontier, test.frontier)
+		}
+	}
+
+	if tree.Contains(f.id("Myst:9")) {
+		t.Error("Myst:9 is unreachable, but in the tree")
+	}
+	if children := names(g, tree.Children(f.id("Myst:1"))); children != "Myst:2 Myst:5 Myst:7" {
+		t.Errorf("got the children %q, expected Myst:2 Myst:5 Myst:7", children)
+	}
+	if dominators := names(g, tree.Dominators(f.id("Myst:8"))); dominators != "Myst:1 Myst:7" {
+		t.Errorf("got the dominators of Myst:8 %q, expected Myst:1 Myst:7", dominators)
+	}
+	if edges := tree.Edges(); len(edges) != 7 {
+		t.Errorf("got %d tree edges, expected 7", len(edges))
+	}
+}
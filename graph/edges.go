@@ -55,6 +55,7 @@ func (g *MystGraph) AddEdge(edge *common.Edge, weight float64) error {
 	if !isDuplicate {
 		newEdge := common.Edge{
 			Attributes:     edge.Attributes,
+			Tags:           edge.Tags,
 			Source:         edge.Source,
 			Target:         edge.Target,
 			TransitivityID: edge.TransitivityID,
@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
+)
+
+// fixture builds small Myst Graphs for the tests; the cards are named `Stack:ID`
+type fixture struct {
+	nodes    map[string]*common.Node
+	metadata *common.Metadata
+}
+
+func newFixture() *fixture {
+	return &fixture{
+		nodes:    make(map[string]*common.Node),
+		metadata: &common.Metadata{},
+	}
+}
+
+// card returns the card with the given name, created if needed, with the given attributes
+func (f *fixture) card(name string, attributes ...common.NodeAttribute) *common.Node {
+	node, exists := f.nodes[name]
+	if !exists {
+		stack, id, _ := strings.Cut(name, ":")
+		cardID, _ := strconv.Atoi(id)
+		node = &common.Node{Name: name, StackName: stack, CardID: cardID, Attributes: []common.NodeAttribute{common.IsCard}}
+		f.nodes[name] = node
+		f.metadata.Nodes = append(f.metadata.Nodes, node)
+	}
+
+	for _, attribute := range attributes {
+		if !node.IsOfType(attribute) {
+			node.Attributes = append(node.Attributes, attribute)
+		}
+	}
+	return node
+}
+
+// link adds an edge with the given attributes (IntraAge or CrossAge is derived from the stacks)
+func (f *fixture) link(from, to string, attributes ...common.EdgeAttribute) *common.Edge {
+	source, target := f.card(from), f.card(to)
+
+	kind := common.IntraAge
+	if source.StackName != target.StackName {
+		kind = common.CrossAge
+	}
+
+	edge := &common.Edge{Source: source, Target: target, Attributes: append([]common.EdgeAttribute{kind}, attributes...)}
+	f.metadata.Edges = append(f.metadata.Edges, edge)
+	return edge
+}
+
+// path links the consecutive cards
+func (f *fixture) path(names ...string) {
+	for i := 1; i < len(names); i++ {
+		f.link(names[i-1], names[i])
+	}
+}
+
+// transitive links the source to the target through the transitive card
+// (restrictive transitivity Tail and Head edges sharing the transitivity ID)
+func (f *fixture) transitive(source, card, target string, transitivityID int64) {
+	f.link(source, card, common.RestrictiveTransitivityTail).TransitivityID = transitivityID
+	f.link(card, target, common.RestrictiveTransitivityHead).TransitivityID = transitivityID
+}
+
+// graph instantiates the graph, with the given entry and goal cards (if any)
+func (f *fixture) graph(t *testing.T, entry string, goals ...string) *MystGraph {
+	t.Helper()
+
+	g, err := NewGraph(f.metadata)
+	if err != nil {
+		t.Fatalf("unable to instantiate the graph: %v", err)
+	}
+
+	profile := config.GenericProfile()
+	if entry != "" {
+		profile.EntryNode = f.ref(entry)
+	}
+	for _, goal := range goals {
+		profile.GoalNodes = append(profile.GoalNodes, *f.ref(goal))
+	}
+	g.Profile = profile
+	g.markProfileNodes()
+
+	return g
+}
+
+// ref returns the profile reference of a card
+func (f *fixture) ref(name string) *config.CardRef {
+	node := f.card(name)
+	return &config.CardRef{Stack: node.StackName, ID: node.CardID}
+}
+
+// id returns the graph ID of a card (see NewGraph)
+func (f *fixture) id(name string) int64 {
+	return f.nodes[name].GraphID
+}
+
+// names converts the graph IDs into card names
+func names(g *MystGraph, ids []int64) string {
+	return strings.Join(g.FormatPathAsNames(ids), " ")
+}
@@ -0,0 +1,54 @@
+package graph
+
+import (
+	"hash/fnv"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// StableNodeID derives the graph ID of a node from its stack name and HyperCard card ID,
+// so that IDs do not depend on the parsing order and remain stable across runs and corpora:
+//
+//	bits 62-32: FNV-1a hash of the stack name (31 bits)
+//	bits 31-0:  HyperCard card ID
+//
+// Stack nodes use the card ID 0 (HyperCard does not use it). Virtual cards referenced by name
+// (no card ID) use the FNV-1a hash of their name with the bit 31 set, as HyperCard card IDs
+// are lower than 2^31.
+//
+// e.g., the ID of `Myst:8336` is `hash("Myst") << 32 | 8336`, so that the card ID can be read
+// from the lower 32 bits of any ID
+func StableNodeID(node *common.Node) int64 {
+	stackName := node.StackName
+	if node.IsOfType(common.IsStack) {
+		stackName = node.Name
+	}
+
+	var cardPart uint32
+	switch {
+	case node.IsOfType(common.IsStack):
+		cardPart = 0
+	case node.CardID != 0:
+		cardPart = uint32(node.CardID)
+	default:
+		cardPart = hash32(node.Name) | 1<<31
+	}
+
+	return int64(hash32(stackName)&0x7fffffff)<<32 | int64(cardPart)
+}
+
+// CardIDFromNodeID returns the HyperCard card ID encoded in a stable node ID
+// (see StableNodeID)
+func CardIDFromNodeID(id int64) (int, bool) {
+	cardPart := uint32(id)
+	if cardPart == 0 || cardPart&(1<<31) != 0 {
+		return 0, false // stack node or virtual card referenced by name
+	}
+	return int(cardPart), true
+}
+
+func hash32(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
@@ -0,0 +1,352 @@
+package graph
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
+
+	"gonum.org/v1/gonum/graph/simple"
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+// ComputeMetrics computes the global metrics of the graph and of each stack
+// NOTE: the stack nodes belong to no stack, and are only counted in the whole graph
+// NOTE: the structural metrics (edges, degrees, reciprocity, clustering, and assortativity)
+// count the links between the cards on the traversable view of the graph: they are the edges of
+// the state graph once the pending states are collapsed into their transitive cards (the Tail
+// and Head edges linking to and from the transitive card), so the pairing of the restrictive
+// transitivity edges does not change them; the eccentricities follow the paths of the state graph
+func (g *MystGraph) ComputeMetrics() common.MetricsReport {
+	view := g.traversableGraph()
+	nodeIDs := g.traverser.getAllNodeIDs()
+
+	report := common.MetricsReport{Graph: g.scopeMetrics(view, "", nodeIDs)}
+
+	stackNodes := make(map[string][]int64)
+	for _, id := range nodeIDs {
+		stack := g.IdStackMap[id]
+		if stack == "" {
+			continue
+		}
+		stackNodes[stack] = append(stackNodes[stack], id)
+	}
+
+	stacks := make([]string, 0, len(stackNodes))
+	for stack := range stackNodes {
+		stacks = append(stacks, stack)
+	}
+	slices.Sort(stacks)
+
+	for _, stack := range stacks {
+		report.Stacks = append(report.Stacks, g.scopeMetrics(view, stack, stackNodes[stack]))
+	}
+
+	return report
+}
+
+// scopeMetrics computes the metrics of a set of nodes (the whole graph, or a stack)
+func (g *MystGraph) scopeMetrics(view *simple.WeightedDirectedGraph, stack string, nodeIDs []int64) common.GraphMetrics {
+	metrics := common.GraphMetrics{Stack: stack, Nodes: len(nodeIDs)}
+
+	inScope := make(map[int64]bool, len(nodeIDs))
+	for _, id := range nodeIDs {
+		inScope[id] = true
+	}
+
+	// edges between the nodes of the scope
+	successors := make(map[int64][]int64, len(nodeIDs))
+	inDegrees := make(map[int64]int, len(nodeIDs))
+	for _, id := range nodeIDs {
+		from := view.From(id)
+		for from.Next() {
+			if toID := from.Node().ID(); inScope[toID] {
+				successors[id] = append(successors[id], toID)
+				inDegrees[toID]++
+			}
+		}
+		metrics.Edges += len(successors[id])
+	}
+
+	if n := len(nodeIDs); n > 1 {
+		metrics.Density = float64(metrics.Edges) / float64(n*(n-1))
+	}
+
+	reciprocal := 0
+	for _, id := range nodeIDs {
+		for _, toID := range successors[id] {
+			if view.HasEdgeFromTo(toID, id) {
+				reciprocal++
+			}
+		}
+	}
+	if metrics.Edges > 0 {
+		metrics.Reciprocity = float64(reciprocal) / float64(metrics.Edges)
+	}
+
+	metrics.AverageClustering = averageClustering(nodeIDs, successors)
+	metrics.Assortativity = degreeAssortativity(nodeIDs, successors, inDegrees)
+
+	inDegreeCounts := make(map[float64]int)
+	outDegreeCounts := make(map[float64]int)
+	for _, id := range nodeIDs {
+		inDegreeCounts[float64(inDegrees[id])]++
+		outDegreeCounts[float64(len(successors[id]))]++
+	}
+	metrics.InDegreeDistribution = histogram(inDegreeCounts)
+	metrics.OutDegreeDistribution = histogram(outDegreeCounts)
+
+	g.eccentricityMetrics(&metrics, stack, inScope)
+
+	// NOTE: the attributes set by the analyses are only recorded in the metadata
+	for _, attribute := range common.NodeAttributes() {
+		count := 0
+		for _, node := range g.Metadata.Nodes {
+			if inScope[node.GraphID] && node.IsOfType(attribute) {
+				count++
+			}
+		}
+		metrics.NodeAttributes = append(metrics.NodeAttributes, common.AttributeCount{Attribute: attribute.String(), Count: count})
+	}
+
+	for _, attribute := range common.EdgeAttributes() {
+		count := 0
+		for _, edge := range g.Metadata.Edges {
+			if inScope[edge.Source.GraphID] && edge.IsOfType(attribute) {
+				count++
+			}
+		}
+		metrics.EdgeAttributes = append(metrics.EdgeAttributes, common.AttributeCount{Attribute: attribute.String(), Count: count})
+	}
+
+	return metrics
+}
+
+// eccentricityMetrics computes the eccentricity of the nodes of the largest strongly connected
+// component of the scope (the greatest distance to its other nodes, on the shortest paths within
+// the scope), the diameter, the radius, the center, and the periphery
+// NOTE: the other nodes do not reach every node of the component (infinite eccentricity),
+// hence the restriction
+func (g *MystGraph) eccentricityMetrics(metrics *common.GraphMetrics, stack string, inScope map[int64]bool) {
+	view := g.pathAnalyzer.weightedGraph()
+
+	// states of the scope: its nodes, and the pending states of its transitive cards
+	scope := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+	nodes := view.Nodes()
+	for nodes.Next() {
+		if id := nodes.Node().ID(); inScope[view.card(id)] {
+			scope.AddNode(simple.Node(id))
+		}
+	}
+	edges := view.WeightedEdges()
+	for edges.Next() {
+		edge := edges.WeightedEdge()
+		if scope.Node(edge.From().ID()) != nil && scope.Node(edge.To().ID()) != nil {
+			scope.SetWeightedEdge(scope.NewWeightedEdge(edge.From(), edge.To(), edge.Weight()))
+		}
+	}
+
+	// largest component (by number of nodes, ties broken by the smallest node ID)
+	var component []int64
+	for _, states := range topo.TarjanSCC(scope) {
+		var nodeIDs []int64
+		for _, state := range states {
+			if id := state.ID(); inScope[id] {
+				nodeIDs = append(nodeIDs, id)
+			}
+		}
+		slices.Sort(nodeIDs)
+
+		if len(nodeIDs) > len(component) || len(nodeIDs) == len(component) && len(nodeIDs) > 0 && nodeIDs[0] < component[0] {
+			component = nodeIDs
+		}
+	}
+
+	if len(component) < 2 {
+		return
+	}
+	metrics.LargestComponent = len(component)
+
+	// NOTE: the shortest paths between the nodes of a component stay in the component,
+	// hence the distance matrix for the whole graph
+	distance := g.pathAnalyzer.distanceMatrix().Distance
+	if stack != "" {
+		distance = scopeDistances(scope, component)
+	}
+
+	eccentricities := make(map[int64]float64, len(component))
+	for _, from := range component {
+		for _, to := range component {
+			eccentricities[from] = math.Max(eccentricities[from], distance(from, to))
+		}
+	}
+
+	counts := make(map[float64]int)
+	metrics.Radius = math.Inf(1)
+	for _, eccentricity := range eccentricities {
+		counts[eccentricity]++
+		metrics.Diameter = math.Max(metrics.Diameter, eccentricity)
+		metrics.Radius = math.Min(metrics.Radius, eccentricity)
+	}
+	metrics.EccentricityDistribution = histogram(counts)
+
+	for _, id := range component {
+		if eccentricities[id] == metrics.Radius {
+			metrics.Center = append(metrics.Center, g.nodeInfo(id))
+		}
+		if eccentricities[id] == metrics.Diameter {
+			metrics.Periphery = append(metrics.Periphery, g.nodeInfo(id))
+		}
+	}
+	sortNodeInfos(metrics.Center)
+	sortNodeInfos(metrics.Periphery)
+}
+
+// scopeDistances returns the distances between the given nodes, on the shortest paths of the scope
+func scopeDistances(scope *simple.WeightedDirectedGraph, nodeIDs []int64) func(from, to int64) float64 {
+	// states: the given nodes first, then the other states of the scope (sorted, for determinism)
+	states := slices.Clone(nodeIDs)
+	stateIndex := make(map[int64]int32, scope.Nodes().Len())
+	for i, id := range states {
+		stateIndex[id] = int32(i)
+	}
+
+	var otherIDs []int64
+	others := scope.Nodes()
+	for others.Next() {
+		id := others.Node().ID()
+		if _, exists := stateIndex[id]; !exists {
+			otherIDs = append(otherIDs, id)
+		}
+	}
+	slices.Sort(otherIDs)
+	for _, id := range otherIDs {
+		stateIndex[id] = int32(len(states))
+		states = append(states, id)
+	}
+
+	adjacency := make([][]arc, len(states))
+	unitWeights := true
+	for i, id := range states {
+		successors := scope.From(id)
+		for successors.Next() {
+			toID := successors.Node().ID()
+			weight := scope.WeightedEdge(id, toID).Weight()
+			if weight != 1 {
+				unitWeights = false
+			}
+			adjacency[i] = append(adjacency[i], arc{to: stateIndex[toID], weight: weight})
+		}
+
+		slices.SortFunc(adjacency[i], func(a, b arc) int {
+			return cmp.Compare(a.to, b.to)
+		})
+	}
+
+	rows := make([][]float64, len(nodeIDs))
+	predecessors := make([]int32, len(states))
+	for i := range nodeIDs {
+		rows[i] = make([]float64, len(states))
+		if unitWeights {
+			breadthFirstDistances(adjacency, int32(i), rows[i], predecessors)
+		} else {
+			dijkstraDistances(adjacency, int32(i), rows[i], predecessors)
+		}
+	}
+
+	return func(from, to int64) float64 {
+		return rows[stateIndex[from]][stateIndex[to]]
+	}
+}
+
+// averageClustering returns the average local clustering coefficient of the nodes: the share
+// of the pairs of neighbours that are linked (edge directions ignored, 0 for less than two neighbours)
+func averageClustering(nodeIDs []int64, successors map[int64][]int64) float64 {
+	if len(nodeIDs) == 0 {
+		return 0
+	}
+
+	neighbours := make(map[int64]map[int64]bool, len(nodeIDs))
+	link := func(a, b int64) {
+		if neighbours[a] == nil {
+			neighbours[a] = make(map[int64]bool)
+		}
+		neighbours[a][b] = true
+	}
+	for id, targets := range successors {
+		for _, toID := range targets {
+			link(id, toID)
+			link(toID, id)
+		}
+	}
+
+	total := 0.0
+	for _, id := range nodeIDs {
+		k := len(neighbours[id])
+		if k < 2 {
+			continue
+		}
+
+		links := 0
+		for a := range neighbours[id] {
+			for b := range neighbours[a] {
+				if a < b && neighbours[id][b] {
+					links++
+				}
+			}
+		}
+		total += 2 * float64(links) / float64(k*(k-1))
+	}
+
+	return total / float64(len(nodeIDs))
+}
+
+// degreeAssortativity returns the Pearson correlation between the out-degree of the source
+// and the in-degree of the target of the edges (0 if undefined, e.g., equal degrees)
+func degreeAssortativity(nodeIDs []int64, successors map[int64][]int64, inDegrees map[int64]int) float64 {
+	var xs, ys []float64
+	for _, id := range nodeIDs {
+		for _, toID := range successors[id] {
+			xs = append(xs, float64(len(successors[id])))
+			ys = append(ys, float64(inDegrees[toID]))
+		}
+	}
+
+	if len(xs) == 0 {
+		return 0
+	}
+
+	var meanX, meanY float64
+	for i := range xs {
+		meanX += xs[i]
+		meanY += ys[i]
+	}
+	meanX /= float64(len(xs))
+	meanY /= float64(len(ys))
+
+	var covariance, varianceX, varianceY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		covariance += dx * dy
+		varianceX += dx * dx
+		varianceY += dy * dy
+	}
+	if varianceX == 0 || varianceY == 0 {
+		return 0
+	}
+
+	return covariance / math.Sqrt(varianceX*varianceY)
+}
+
+// histogram converts counts into bins sorted by value
+func histogram(counts map[float64]int) []common.HistogramBin {
+	bins := make([]common.HistogramBin, 0, len(counts))
+	for value, count := range counts {
+		bins = append(bins, common.HistogramBin{Value: value, Count: count})
+	}
+	slices.SortFunc(bins, func(a, b common.HistogramBin) int {
+		return cmp.Compare(a.Value, b.Value)
+	})
+	return bins
+}
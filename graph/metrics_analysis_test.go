@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+func TestEccentricityMetrics(t *testing.T) {
+	f := newFixture()
+	// Myst: a cycle with a chord, and a dead end
+	f.path("Myst:1", "Myst:2", "Myst:3", "Myst:4", "Myst:1")
+	f.path("Myst:1", "Myst:3")
+	f.path("Myst:4", "Myst:5")
+	// Channelwood: a shortcut from Myst:2 to Myst:1, outside of the Myst stack
+	f.path("Myst:4", "Channelwood:10", "Myst:1")
+	f.path("Myst:2", "Channelwood:10", "Channelwood:11", "Channelwood:10")
+	// Selenitic: the paths pass through the transitive card
+	f.transitive("Selenitic:20", "Selenitic:21", "Selenitic:22", 1)
+	f.path("Selenitic:22", "Selenitic:20")
+	g := f.graph(t, "")
+
+	nodeNames := func(nodes []common.NodeInfo) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Name)
+		}
+		return result
+	}
+	format := func(metrics common.GraphMetrics) string {
+		return fmt.Sprintf("%s: %d nodes: radius %v %q, diameter %v %q, %v",
+			metrics.Stack, metrics.LargestComponent, metrics.Radius, nodeNames(metrics.Center),
+			metrics.Diameter, nodeNames(metrics.Periphery), metrics.EccentricityDistribution)
+	}
+
+	report := g.ComputeMetrics()
+
+	tests := []struct {
+		metrics  common.GraphMetrics
+		expected string
+	}{
+		{report.Graph, `: 6 nodes: radius 2 ["Myst:2" "Myst:4"], diameter 4 ["Channelwood:11"], [{2 2} {3 3} {4 1}]`},
+		{report.Stacks[0], `Channelwood: 2 nodes: radius 1 ["Channelwood:10" "Channelwood:11"], diameter 1 ["Channelwood:10" "Channelwood:11"], [{1 2}]`},
+		// NOTE: the shortest path from Myst:2 to Myst:1 leaves the stack
+		{report.Stacks[1], `Myst: 4 nodes: radius 2 ["Myst:1" "Myst:4"], diameter 3 ["Myst:2" "Myst:3"], [{2 2} {3 2}]`},
+		{report.Stacks[2], `Selenitic: 2 nodes: radius 1 ["Selenitic:22"], diameter 2 ["Selenitic:20"], [{1 1} {2 1}]`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.metrics.Stack, func(t *testing.T) {
+			if metrics := format(test.metrics); metrics != test.expected {
+				t.Errorf("got:\n%s\nexpected:\n%s", metrics, test.expected)
+			}
+		})
+	}
+}
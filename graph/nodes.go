@@ -24,12 +24,17 @@ func (g *MystGraph) AddNode(node *common.Node) error {
 		return common.NodeAlreadyExistsErr
 	}
 
-	// create a new node with the next available ID
-	node.GraphID = g.Graph.NewNode().ID()
+	// derive the ID from the stack name and the card ID (see StableNodeID)
+	node.GraphID = StableNodeID(node)
+	if g.Graph.Node(node.GraphID) != nil {
+		return fmt.Errorf("%s (ID %d, already used by %s): %w",
+			node.Name, node.GraphID, g.GetNameForID(node.GraphID), common.NodeIDCollisionErr)
+	}
 	g.Graph.AddNode(multi.Node(node.GraphID))
 
 	g.NodeMap[node.Name] = *node
 	g.IdNameMap[node.GraphID] = node.Name
+	g.IdStackMap[node.GraphID] = node.StackName
 
 	return nil
 }
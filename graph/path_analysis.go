@@ -9,17 +9,71 @@ import (
 
 	gograph "gonum.org/v1/gonum/graph"
 	"gonum.org/v1/gonum/graph/path"
+	"gonum.org/v1/gonum/graph/simple"
 )
 
 // pathAnalyzer is a utility for path finding and analysis
 type pathAnalyzer struct {
 	g *MystGraph
+	// state graph (see transitivityGraph), built on first use
+	view *stateGraph
+	// state graph of the pushdown search (see pushdownGraph), built on first use
+	pushdownView *stateGraph
+	// shortest paths between all pairs of nodes (see distanceMatrix), computed on first use
+	distances *common.DistanceMatrix
 }
 
 func newPathAnalyzer(g *MystGraph) *pathAnalyzer {
 	return &pathAnalyzer{g: g}
 }
 
+// weightedGraph returns the state graph the paths are computed on: the edges weighted by
+// the cost model (without the ones that cannot be traversed), and the restrictive transitivity
+// edges expanded (a Tail edge is always followed by the Head edge with the same ID)
+// NOTE: the paths found in this graph must be converted back into cards (see stateGraph.cardPath)
+func (pa *pathAnalyzer) weightedGraph() *stateGraph {
+	if pa.view == nil {
+		pa.view = pa.g.transitivityGraph()
+	}
+	return pa.view
+}
+
+// pushdownGraph returns the state graph of the push card / pop card discipline: the state graph
+// of the path analyses, the backtracking edges (to a popping card) weighted as regular edges
+func (pa *pathAnalyzer) pushdownGraph() *stateGraph {
+	if pa.pushdownView == nil {
+		pa.pushdownView = pa.g.buildStateGraph(common.Backtracking)
+	}
+	return pa.pushdownView
+}
+
+// reachableCards returns the cards reachable from the given card through the paths
+// of the state graph (see weightedGraph)
+// NOTE: the transitive cards are passed through (pending states of the state graph)
+func (g *MystGraph) reachableCards(from int64) map[int64]bool {
+	view := g.pathAnalyzer.weightedGraph()
+
+	cards := map[int64]bool{from: true}
+	visited := map[int64]bool{from: true}
+	queue := []int64{from}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		successors := view.From(id)
+		for successors.Next() {
+			toID := successors.Node().ID()
+			if !visited[toID] {
+				visited[toID] = true
+				cards[view.card(toID)] = true
+				queue = append(queue, toID)
+			}
+		}
+	}
+
+	return cards
+}
+
 // FindMostSeparatedNodes identifies the pair of connected nodes with the longest shortest path
 func (g *MystGraph) FindMostSeparatedNodes() common.NodePairInfo {
 	result := g.pathAnalyzer.findMostSeparatedNodes()
@@ -28,8 +82,9 @@ func (g *MystGraph) FindMostSeparatedNodes() common.NodePairInfo {
 }
 
 // ComputeAllShortestPaths calculates the shortest paths between all pairs of nodes
-func (g *MystGraph) ComputeAllShortestPaths() map[int64]map[int64]common.ShortestPathInfo {
-	return g.pathAnalyzer.computeShortestPaths()
+// (see common.DistanceMatrix)
+func (g *MystGraph) ComputeAllShortestPaths() *common.DistanceMatrix {
+	return g.pathAnalyzer.distanceMatrix()
 }
 
 // ComputeShortestPath calculates the shortest path between two nodes
@@ -54,70 +109,6 @@ func (g *MystGraph) GetMostSeparatedNodePath() ([]int64, bool) {
 	return pathCopy, true
 }
 
-// computeShortestPaths calculates all shortest paths in the graph using Dijkstra
-func (pa *pathAnalyzer) computeShortestPaths() map[int64]map[int64]common.ShortestPathInfo {
-	result := make(map[int64]map[int64]common.ShortestPathInfo)
-
-	nodeIDs := pa.g.traverser.getAllNodeIDs()
-
-	for _, src := range nodeIDs {
-		result[src] = make(map[int64]common.ShortestPathInfo)
-
-		// use Dijkstra's algorithm from Gonum
-		p := path.DijkstraFrom(pa.g.Node(src), pa.g)
-
-		// for each destination node...
-		for _, dst := range nodeIDs {
-			if src == dst {
-				continue // Skip self-paths
-			}
-
-			// ... get the shortest path
-			pathNodes, weight := p.To(dst)
-
-			// check if there is a path
-			if len(pathNodes) == 0 {
-				// no path found, this could be due to disabled or backtracking edges
-				continue
-			}
-
-			pathInfo := common.ShortestPathInfo{
-				From:     src,
-				To:       dst,
-				Distance: weight,
-				Path:     []int64{},
-			}
-
-			for _, node := range pathNodes {
-				pathInfo.Path = append(pathInfo.Path, node.ID())
-			}
-
-			// validation: check that no backtracking or disabled edge exists in this path
-			valid := true
-			for i := 0; i < len(pathInfo.Path)-1; i++ {
-				fromID := pathInfo.Path[i]
-				toID := pathInfo.Path[i+1]
-
-				// check if this edge is backtracking or disabled
-				if edges, exists := pa.g.GetAllEdges(fromID, toID); exists {
-					for _, edge := range edges {
-						if edge.IsOfType(common.Backtracking) || edge.IsOfType(common.Disabled) {
-							valid = false
-							break
-						}
-					}
-				}
-			}
-
-			if valid {
-				result[src][dst] = pathInfo
-			}
-		}
-	}
-
-	return result
-}
-
 // computeShortestPath calculates the shortest path between two nodes
 func (pa *pathAnalyzer) computeShortestPath(from, to int64, mandatoryNodes []common.Node) (*common.ShortestPathInfo, error) {
 	if pa.g.Node(from) == nil {
@@ -180,7 +171,7 @@ func (pa *pathAnalyzer) computeShortestPath(from, to int64, mandatoryNodes []com
 		return &shortestPath, nil
 	}
 
-	p := path.DijkstraFrom(pa.g.Node(from), pa.g)
+	p := path.DijkstraFrom(simple.Node(from), pa.weightedGraph())
 	pathNodes, weight := p.To(to)
 
 	pathInfo := common.ShortestPathInfo{
@@ -191,9 +182,7 @@ func (pa *pathAnalyzer) computeShortestPath(from, to int64, mandatoryNodes []com
 	}
 
 	if len(pathNodes) > 0 {
-		for _, node := range pathNodes {
-			pathInfo.Path = append(pathInfo.Path, node.ID())
-		}
+		pathInfo.Path = pa.weightedGraph().cardPath(pathNodeIDs(pathNodes))
 	} else {
 		return &pathInfo, fmt.Errorf("no path exists from %d to %d", from, to)
 	}
@@ -228,8 +217,10 @@ func (pa *pathAnalyzer) computeShortestPath(from, to int64, mandatoryNodes []com
 }
 
 // findAllShortestPaths finds all possible shortest paths between two nodes with a given target weight
+// NOTE: the paths are explored in the state graph (see weightedGraph), then converted into cards
 func (pa *pathAnalyzer) findAllShortestPaths(from, to int64, targetWeight float64) [][]int64 {
 	allPaths := [][]int64{}
+	view := pa.weightedGraph()
 
 	var explore func(current int64, visited map[int64]bool, path []int64, currentWeight float64)
 	explore = func(current int64, visited map[int64]bool, path []int64, currentWeight float64) {
@@ -238,9 +229,7 @@ func (pa *pathAnalyzer) findAllShortestPaths(from, to int64, targetWeight float6
 			// ... and this is a shortest path (weight matches target)...
 			if math.Abs(currentWeight-targetWeight) < 1e-9 {
 				// ... make a copy of the path to avoid reference issues
-				pathCopy := make([]int64, len(path))
-				copy(pathCopy, path)
-				allPaths = append(allPaths, pathCopy)
+				allPaths = append(allPaths, view.cardPath(path))
 			}
 			return
 		}
@@ -253,8 +242,8 @@ func (pa *pathAnalyzer) findAllShortestPaths(from, to int64, targetWeight float6
 		// mark the current node as visited
 		visited[current] = true
 
-		// explore all neighbors
-		neighbors := pa.g.From(current)
+		// explore all neighbors (through the traversable edges)
+		neighbors := view.From(current)
 
 		var sortedNeighbors []struct {
 			id     int64
@@ -269,16 +258,8 @@ func (pa *pathAnalyzer) findAllShortestPaths(from, to int64, targetWeight float6
 				continue
 			}
 
-			// get the edge weight
-			edge := pa.g.Edge(current, neighborID)
-
-			// skip backtracking and disabled edges
-			if pa.g.IsBacktrackingEdge(current, neighborID) || pa.g.IsDisabledEdge(current, neighborID) {
-				continue
-			}
-
 			// check if this edge would stay within the target weight
-			edgeWeight := edge.(gograph.WeightedEdge).Weight()
+			edgeWeight := view.WeightedEdge(current, neighborID).Weight()
 			if currentWeight+edgeWeight <= targetWeight+1e-9 {
 				sortedNeighbors = append(sortedNeighbors, struct {
 					id     int64
@@ -328,35 +309,25 @@ func (pa *pathAnalyzer) findMostSeparatedNodes() common.NodePairInfo {
 		source   int64
 		target   int64
 		distance float64
-		path     []int64
 	}
 
 	var candidates []pathCandidate
 
-	// check all pairs of nodes
-	for _, src := range nodeIDs {
-		// calculate the shortest paths from this source
-		p := path.DijkstraFrom(pa.g.Node(src), pa.g)
+	// check all pairs of nodes (see distanceMatrix)
+	matrix := pa.distanceMatrix()
+	for i, src := range matrix.Nodes {
+		distances := matrix.Distances[i*len(matrix.States):]
 
-		for _, dst := range nodeIDs {
-			if src == dst {
-				continue // skip self
-			}
-
-			pathNodes, weight := p.To(dst)
+		for j, dst := range matrix.Nodes {
+			weight := distances[j]
 
-			// skip unreachable nodes or paths using backtracking edges
-			if len(pathNodes) == 0 || weight == math.Inf(1) {
+			// skip self and unreachable nodes
+			if i == j || math.IsInf(weight, 1) {
 				continue
 			}
 
 			// for reachable nodes with valid paths, track if this is a candidate
 			if weight >= maxFiniteDistance {
-				var pathIDs []int64
-				for _, node := range pathNodes {
-					pathIDs = append(pathIDs, node.ID())
-				}
-
 				if weight > maxFiniteDistance {
 					maxFiniteDistance = weight
 					candidates = []pathCandidate{}
@@ -366,7 +337,6 @@ func (pa *pathAnalyzer) findMostSeparatedNodes() common.NodePairInfo {
 					source:   src,
 					target:   dst,
 					distance: weight,
-					path:     pathIDs,
 				})
 			}
 		}
@@ -388,7 +358,7 @@ func (pa *pathAnalyzer) findMostSeparatedNodes() common.NodePairInfo {
 				Name: pa.g.GetNameForID(candidate.target),
 			},
 			Distance: candidate.distance,
-			Path:     candidate.path,
+			Path:     pa.candidatePath(candidate.source, candidate.target),
 		}
 	}
 
@@ -434,8 +404,23 @@ func (pa *pathAnalyzer) findMostSeparatedNodes() common.NodePairInfo {
 			Name: pa.g.GetNameForID(bestCandidate.target),
 		},
 		Distance: bestCandidate.distance,
-		Path:     bestCandidate.path,
+		Path:     pa.candidatePath(bestCandidate.source, bestCandidate.target),
 	}
 
 	return result
 }
+
+// candidatePath returns the shortest path between two nodes (see distanceMatrix)
+func (pa *pathAnalyzer) candidatePath(from, to int64) []int64 {
+	shortestPath, _ := pa.distanceMatrix().ShortestPath(from, to)
+	return shortestPath.Path
+}
+
+// pathNodeIDs returns the IDs of the nodes of a path
+func pathNodeIDs(nodes []gograph.Node) []int64 {
+	ids := make([]int64, len(nodes))
+	for i, node := range nodes {
+		ids[i] = node.ID()
+	}
+	return ids
+}
@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+// crossedTransitivity returns two transitive cards whose Tail and Head edges cross:
+// going through Myst:10 from Myst:1 leads to Myst:20 only, and from Myst:2 to Myst:30 only
+// (likewise through Myst:11), so the shortcuts Myst:1 -> Myst:10 -> Myst:30 and
+// Myst:20 -> Myst:11 -> Myst:41 exist in the traversable graph but are not valid paths
+func crossedTransitivity() (*fixture, map[string]map[[2]string]bool) {
+	f := newFixture()
+	f.transitive("Myst:1", "Myst:10", "Myst:20", 1)
+	f.transitive("Myst:2", "Myst:10", "Myst:30", 2)
+	f.transitive("Myst:20", "Myst:11", "Myst:40", 3)
+	f.transitive("Myst:30", "Myst:11", "Myst:41", 4)
+	f.path("Myst:40", "Myst:2")
+
+	pairings := map[string]map[[2]string]bool{
+		"Myst:10": {{"Myst:1", "Myst:20"}: true, {"Myst:2", "Myst:30"}: true},
+		"Myst:11": {{"Myst:20", "Myst:40"}: true, {"Myst:30", "Myst:41"}: true},
+	}
+	return f, pairings
+}
+
+// checkPairings fails if the path goes through a transitive card along a Tail and a Head edge
+// that do not share the transitivity ID
+func checkPairings(t *testing.T, g *MystGraph, path []int64, pairings map[string]map[[2]string]bool) {
+	t.Helper()
+
+	cards := g.FormatPathAsNames(path)
+	for i := 1; i < len(cards)-1; i++ {
+		allowed, transitive := pairings[cards[i]]
+		if transitive && !allowed[[2]string{cards[i-1], cards[i+1]}] {
+			t.Errorf("path %v goes through %s from %s to %s", cards, cards[i], cards[i-1], cards[i+1])
+		}
+	}
+}
+
+func TestTransitivityPairing(t *testing.T) {
+	f, pairings := crossedTransitivity()
+	g := f.graph(t, "Myst:1")
+
+	tests := []struct {
+		from, to string
+		distance float64
+		path     string
+	}{
+		{"Myst:1", "Myst:20", 2, "Myst:1 Myst:10 Myst:20"},
+		{"Myst:1", "Myst:30", 7, "Myst:1 Myst:10 Myst:20 Myst:11 Myst:40 Myst:2 Myst:10 Myst:30"},
+		{"Myst:1", "Myst:41", 9, "Myst:1 Myst:10 Myst:20 Myst:11 Myst:40 Myst:2 Myst:10 Myst:30 Myst:11 Myst:41"},
+		{"Myst:20", "Myst:41", 7, "Myst:20 Myst:11 Myst:40 Myst:2 Myst:10 Myst:30 Myst:11 Myst:41"},
+		{"Myst:2", "Myst:20", math.Inf(1), ""},
+		{"Myst:30", "Myst:40", math.Inf(1), ""},
+	}
+
+	matrix := g.pathAnalyzer.distanceMatrix()
+	for _, test := range tests {
+		t.Run(test.from+" -> "+test.to, func(t *testing.T) {
+			from, to := f.id(test.from), f.id(test.to)
+
+			if distance := matrix.Distance(from, to); distance != test.distance {
+				t.Errorf("the distance matrix gives %v, expected %v", distance, test.distance)
+			}
+			if shortestPath, found := matrix.ShortestPath(from, to); found != !math.IsInf(test.distance, 1) || names(g, shortestPath.Path) != test.path {
+				t.Errorf("the distance matrix gives the path %q, expected %q", names(g, shortestPath.Path), test.path)
+			}
+
+			shortestPath, err := g.ComputeShortestPath(from, to, nil)
+			if math.IsInf(test.distance, 1) {
+				if err == nil {
+					t.Errorf("expected no path, got %q", names(g, shortestPath.Path))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if shortestPath.Distance != test.distance || names(g, shortestPath.Path) != test.path {
+				t.Errorf("got %q (%v), expected %q (%v)", names(g, shortestPath.Path), shortestPath.Distance, test.path, test.distance)
+			}
+		})
+	}
+
+	t.Run("most separated nodes", func(t *testing.T) {
+		pair := g.FindMostSeparatedNodes()
+		if pair.Source.Name != "Myst:1" || pair.Target.Name != "Myst:41" || pair.Distance != 9 {
+			t.Errorf("got %s -> %s (%v), expected Myst:1 -> Myst:41 (9)", pair.Source.Name, pair.Target.Name, pair.Distance)
+		}
+		if len(pair.Path) != int(pair.Distance)+1 {
+			t.Errorf("the path %q does not match the distance %v", names(g, pair.Path), pair.Distance)
+		}
+		checkPairings(t, g, pair.Path, pairings)
+	})
+}
@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// tags of the edges turning in place (e.g., set by a rules file matching the hotspot scripts)
+const (
+	TurnLeftTag  = "TurnLeft"
+	TurnRightTag = "TurnRight"
+)
+
+// evidence of the position groups
+const (
+	rotationLoopEvidence     = "rotation loop"
+	sharedBackgroundEvidence = "turn cycle sharing a background"
+	turnHotspotEvidence      = "turn hotspot"
+)
+
+// ReconstructPositions groups the cards into physical positions, and builds the position-level graph
+// The cards of a position are linked by:
+//   - rotation loops (4 cards turning in place, in both directions)
+//   - shorter reversible cycles (2 or 3 cards) whose cards share the same background
+//   - edges tagged TurnLeft or TurnRight (hotspot direction, when available)
+//
+// NOTE: the positions never span several stacks; the cycles must have been enumerated (see FindCycles)
+func (g *MystGraph) ReconstructPositions() common.PositionStats {
+	nodeIDs := g.traverser.getAllNodeIDs()
+	groups := newUnionFind(nodeIDs)
+	evidence := make(map[int64][]string) // node ID -> evidence
+
+	addEvidence := func(nodes []int64, reason string) {
+		for _, id := range nodes {
+			if !slices.Contains(evidence[id], reason) {
+				evidence[id] = append(evidence[id], reason)
+			}
+		}
+	}
+
+	for _, cycle := range g.Metadata.Stats.Cycles {
+		if len(cycle.Stacks) != 1 || len(cycle.Nodes) > rotationLoopLength {
+			continue
+		}
+
+		switch {
+		case cycle.Kind == common.RotationLoop:
+			groups.unionAll(cycle.Nodes)
+			addEvidence(cycle.Nodes, rotationLoopEvidence)
+		case g.isReversibleCycle(cycle.Nodes) && g.shareBackground(cycle.Nodes):
+			groups.unionAll(cycle.Nodes)
+			addEvidence(cycle.Nodes, sharedBackgroundEvidence)
+		}
+	}
+
+	for _, edge := range g.Metadata.Edges {
+		if edge.IsOfType(common.Disabled) || edge.Source.GraphID == edge.Target.GraphID {
+			continue
+		}
+
+		if (edge.HasTag(TurnLeftTag) || edge.HasTag(TurnRightTag)) &&
+			g.StackOf(edge.Source.GraphID) == g.StackOf(edge.Target.GraphID) {
+			nodes := []int64{edge.Source.GraphID, edge.Target.GraphID}
+			groups.unionAll(nodes)
+			addEvidence(nodes, turnHotspotEvidence)
+		}
+	}
+
+	// positions, sorted by stack, then by smallest card ID
+	members := make(map[int64][]int64)
+	for _, id := range nodeIDs {
+		root := groups.find(id)
+		members[root] = append(members[root], id)
+	}
+
+	var positions []common.PositionInfo
+	for _, cards := range members {
+		position := common.PositionInfo{
+			Stack: g.StackOf(cards[0]),
+			Cards: cards,
+		}
+		for _, id := range cards {
+			for _, reason := range evidence[id] {
+				if !slices.Contains(position.Evidence, reason) {
+					position.Evidence = append(position.Evidence, reason)
+				}
+			}
+		}
+		sort.Strings(position.Evidence)
+		positions = append(positions, position)
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Stack != positions[j].Stack {
+			return positions[i].Stack < positions[j].Stack
+		}
+		return positions[i].Cards[0] < positions[j].Cards[0]
+	})
+
+	stats := common.PositionStats{
+		Positions:    positions,
+		NodePosition: make(map[int64]int, len(nodeIDs)),
+	}
+	for i := range positions {
+		positions[i].ID = i
+		for _, id := range positions[i].Cards {
+			stats.NodePosition[id] = i
+		}
+	}
+
+	// position edges (the backtracking and disabled edges are ignored)
+	view := g.traversableGraph()
+	counts := make(map[[2]int]int)
+	edges := view.Edges()
+	for edges.Next() {
+		from := stats.NodePosition[edges.Edge().From().ID()]
+		to := stats.NodePosition[edges.Edge().To().ID()]
+		if from != to {
+			counts[[2]int{from, to}]++
+		}
+	}
+
+	for key, count := range counts {
+		stats.Edges = append(stats.Edges, common.PositionEdge{From: key[0], To: key[1], Count: count})
+	}
+	sort.Slice(stats.Edges, func(i, j int) bool {
+		if stats.Edges[i].From != stats.Edges[j].From {
+			return stats.Edges[i].From < stats.Edges[j].From
+		}
+		return stats.Edges[i].To < stats.Edges[j].To
+	})
+
+	return stats
+}
+
+// isReversibleCycle reports whether the cycle can also be followed backwards
+func (g *MystGraph) isReversibleCycle(nodes []int64) bool {
+	for i, from := range nodes {
+		if !g.HasEdgeFromTo(nodes[(i+1)%len(nodes)], from) {
+			return false
+		}
+	}
+	return true
+}
+
+// shareBackground reports whether all the nodes have the same (known) background
+func (g *MystGraph) shareBackground(nodes []int64) bool {
+	var background string
+	for i, id := range nodes {
+		node, exists := g.NodeMap[g.GetNameForID(id)]
+		if !exists || node.SecondaryName == nil || *node.SecondaryName == "" {
+			return false
+		}
+
+		if i == 0 {
+			background = *node.SecondaryName
+		} else if *node.SecondaryName != background {
+			return false
+		}
+	}
+	return true
+}
+
+// unionFind is a disjoint-set forest over node IDs
+// NOTE: the smallest ID of a set is its root, for deterministic groups
+type unionFind struct {
+	parent map[int64]int64
+}
+
+func newUnionFind(ids []int64) *unionFind {
+	uf := &unionFind{parent: make(map[int64]int64, len(ids))}
+	for _, id := range ids {
+		uf.parent[id] = id
+	}
+	return uf
+}
+
+// find returns the root of the set containing the ID (with path halving)
+func (uf *unionFind) find(id int64) int64 {
+	for uf.parent[id] != id {
+		uf.parent[id] = uf.parent[uf.parent[id]]
+		id = uf.parent[id]
+	}
+	return id
+}
+
+// unionAll merges the sets containing the IDs
+func (uf *unionFind) unionAll(ids []int64) {
+	for _, id := range ids[1:] {
+		a, b := uf.find(ids[0]), uf.find(id)
+		if a == b {
+			continue
+		}
+		if b < a {
+			a, b = b, a
+		}
+		uf.parent[b] = a
+	}
+}
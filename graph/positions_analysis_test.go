@@ -0,0 +1,104 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestUnionFind(t *testing.T) {
+	uf := newUnionFind([]int64{1, 2, 3, 4, 5, 6})
+	uf.unionAll([]int64{5, 3})
+	uf.unionAll([]int64{6, 2})
+	uf.unionAll([]int64{3, 6})
+	uf.unionAll([]int64{4})
+
+	// the smallest ID of a set is its root
+	for id, root := range map[int64]int64{1: 1, 2: 2, 3: 2, 4: 4, 5: 2, 6: 2} {
+		if found := uf.find(id); found != root {
+			t.Errorf("%d: got the root %d, expected %d", id, found, root)
+		}
+	}
+}
+
+func TestReconstructPositions(t *testing.T) {
+	f := newFixture()
+
+	// rotation loop, turning in both directions
+	f.path("Myst:1", "Myst:2", "Myst:3", "Myst:4", "Myst:1")
+	f.path("Myst:1", "Myst:4", "Myst:3", "Myst:2", "Myst:1")
+
+	// reversible cycles, with and without a shared background
+	background := "PICT 1000"
+	f.card("Channelwood:10").SecondaryName = &background
+	f.card("Channelwood:11").SecondaryName = &background
+	f.path("Channelwood:10", "Channelwood:11", "Channelwood:10")
+	f.path("Channelwood:12", "Channelwood:13", "Channelwood:12")
+	f.path("Channelwood:11", "Channelwood:12")
+	f.link("Channelwood:11", "Channelwood:12")
+
+	// turn hotspots, within a stack and across stacks
+	f.link("Selenitic:20", "Selenitic:21").Tags = []string{TurnLeftTag}
+	f.link("Myst:1", "Channelwood:10").Tags = []string{TurnRightTag}
+
+	g := f.graph(t, "")
+	g.Metadata.Stats.Cycles, _ = findCycles(g, DefaultCycleOptions())
+	stats := g.ReconstructPositions()
+
+	var positions []string
+	for i, position := range stats.Positions {
+		if position.ID != i || !slices.IsSorted(position.Cards) {
+			t.Errorf("position %d: got the ID %d and the cards %v", i, position.ID, position.Cards)
+		}
+		for _, id := range position.Cards {
+			if stats.NodePosition[id] != i {
+				t.Errorf("%s: got the position %d, expected %d", g.GetNameForID(id), stats.NodePosition[id], i)
+			}
+		}
+		positions = append(positions, fmt.Sprintf("%s %q", sortedNames(g, position.Cards), position.Evidence))
+	}
+
+	expected := []string{
+		`Channelwood:10 Channelwood:11 ["turn cycle sharing a background"]`,
+		`Channelwood:12 []`,
+		`Channelwood:13 []`,
+		`Myst:1 Myst:2 Myst:3 Myst:4 ["rotation loop"]`,
+		`Selenitic:20 Selenitic:21 ["turn hotspot"]`,
+	}
+	if sorted := slices.Sorted(slices.Values(positions)); !slices.Equal(sorted, expected) {
+		t.Errorf("got the positions:\n%q\nexpected:\n%q", sorted, expected)
+	}
+
+	// the positions are sorted by stack
+	for i := 1; i < len(stats.Positions); i++ {
+		if stats.Positions[i-1].Stack > stats.Positions[i].Stack {
+			t.Errorf("the positions are not sorted by stack: %s before %s", stats.Positions[i-1].Stack, stats.Positions[i].Stack)
+		}
+	}
+
+	position := func(name string) int {
+		return stats.NodePosition[f.id(name)]
+	}
+	var edges []string
+	for _, edge := range stats.Edges {
+		edges = append(edges, fmt.Sprintf("%d %d %d", edge.From, edge.To, edge.Count))
+	}
+	// NOTE: the parallel edges from Channelwood:11 to Channelwood:12 are merged
+	expectedEdges := []string{
+		fmt.Sprintf("%d %d 1", position("Myst:1"), position("Channelwood:10")),
+		fmt.Sprintf("%d %d 1", position("Channelwood:11"), position("Channelwood:12")),
+		fmt.Sprintf("%d %d 1", position("Channelwood:12"), position("Channelwood:13")),
+		fmt.Sprintf("%d %d 1", position("Channelwood:13"), position("Channelwood:12")),
+	}
+	slices.Sort(edges)
+	slices.Sort(expectedEdges)
+	if !slices.Equal(edges, expectedEdges) {
+		t.Errorf("got the position edges %q, expected %q", edges, expectedEdges)
+	}
+}
+
+// sortedNames returns the names of the nodes, sorted
+func sortedNames(g *MystGraph, ids []int64) string {
+	return strings.Join(slices.Sorted(slices.Values(g.FormatPathAsNames(ids))), " ")
+}
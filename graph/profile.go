@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"fmt"
+
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
+)
+
+// GetCardNodeID returns the ID of the node representing the referenced card
+func (g *MystGraph) GetCardNodeID(ref config.CardRef) (int64, error) {
+	id, ok := g.GetNodeID(ref.NodeName())
+	if !ok {
+		return -1, fmt.Errorf("%s: %w", ref, common.NodeNotFoundErr)
+	}
+	return id, nil
+}
+
+// EntryNodeID returns the ID of the profile entry node
+func (g *MystGraph) EntryNodeID() (int64, error) {
+	if g.Profile == nil || g.Profile.EntryNode == nil {
+		return -1, fmt.Errorf("the profile defines no entry node")
+	}
+	return g.GetCardNodeID(*g.Profile.EntryNode)
+}
+
+// GoalNodeIDs returns the IDs of the profile goal nodes found in the graph
+func (g *MystGraph) GoalNodeIDs() []int64 {
+	if g.Profile == nil {
+		return nil
+	}
+
+	var ids []int64
+	for _, ref := range g.Profile.GoalNodes {
+		if id, err := g.GetCardNodeID(ref); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// markProfileNodes sets the entry and goal attributes of the profile nodes
+func (g *MystGraph) markProfileNodes() {
+	if id, err := g.EntryNodeID(); err == nil {
+		g.addNodeAttribute(id, common.IsEntry)
+	}
+
+	for _, id := range g.GoalNodeIDs() {
+		g.addNodeAttribute(id, common.IsGoal)
+	}
+}
+
+// ComputeGoalPaths calculates the shortest path from the entry node to each goal node,
+// respecting the push card / pop card discipline (see ComputePushdownPath)
+// (unreachable goals are skipped)
+func (g *MystGraph) ComputeGoalPaths() []common.ShortestPathInfo {
+	entryID, err := g.EntryNodeID()
+	if err != nil {
+		return nil
+	}
+
+	var paths []common.ShortestPathInfo
+	for _, goalID := range g.GoalNodeIDs() {
+		if p, err := g.ComputePushdownPath(entryID, goalID, nil); err == nil {
+			paths = append(paths, common.ShortestPathInfo{From: p.From, To: p.To, Distance: p.Distance, Path: p.Path})
+		}
+	}
+	return paths
+}
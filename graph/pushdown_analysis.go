@@ -0,0 +1,313 @@
+package graph
+
+import (
+	"cmp"
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// PushdownStep is the kind of a transition of a pushdown path
+type PushdownStep string
+
+const (
+	InternalStep PushdownStep = "internal" // link from a card that does not push itself
+	PushStep     PushdownStep = "push"     // link from a card pushing itself (`push card`)
+	PopStep      PushdownStep = "pop"      // return to the last pushed card (`pop card`)
+)
+
+// PushdownPath is a path respecting the push card / pop card discipline
+type PushdownPath struct {
+	From     int64
+	To       int64
+	Distance float64 // cost of the path (see CostModel)
+	Path     []int64
+	Steps    []PushdownStep // Steps[i] leads from Path[i] to Path[i+1]
+}
+
+// pushdownState is a configuration of the pushdown search
+// NOTE: returning to a card pushed during the search always leads back to an already
+// visited configuration (the one that pushed it), so that the pushed cards do not need
+// to be recorded: only the number of remaining cards of the initial stack (context),
+// and whether cards have been pushed on top of them
+type pushdownState struct {
+	state  int64 // state of the state graph (see pushdownGraph)
+	depth  int   // number of cards of the context still on the stack
+	pushed bool  // cards pushed since the start, on top of the context
+}
+
+// ComputePushdownPath computes the path with the lowest cost (see CostModel) between two nodes,
+// `pop card` returning to the last pushed card: the last card of the context (the cards already
+// pushed, from bottom to top), or a card pushed along the path
+// NOTE: the links from a pushing card are followed whatever their attributes (including the
+// Backtracking ones), except the disabled links; the restrictive transitivity is respected
+func (g *MystGraph) ComputePushdownPath(from, to int64, context []int64) (*PushdownPath, error) {
+	for _, id := range append([]int64{from, to}, context...) {
+		if _, exists := g.IdNameMap[id]; !exists {
+			return nil, fmt.Errorf("%w: %d", common.NodeNotFoundErr, id)
+		}
+	}
+
+	view := g.pathAnalyzer.pushdownGraph()
+
+	var result *PushdownPath
+	g.pushdownSearch(view, from, context, func(state pushdownState, distance float64, path []int64, steps []PushdownStep) bool {
+		if state.state != to {
+			return true
+		}
+
+		result = &PushdownPath{
+			From:     from,
+			To:       to,
+			Distance: distance,
+			Path:     path,
+			Steps:    steps,
+		}
+		return false
+	})
+
+	if result == nil {
+		return nil, fmt.Errorf("no path exists from %d to %d", from, to)
+	}
+	return result, nil
+}
+
+// PushdownReachable returns the cost of the path to each node reachable from the given node
+// under the push card / pop card discipline (see ComputePushdownPath)
+func (g *MystGraph) PushdownReachable(from int64, context []int64) map[int64]float64 {
+	distances := make(map[int64]float64)
+	g.pushdownSearch(g.pathAnalyzer.pushdownGraph(), from, context, func(state pushdownState, distance float64, path []int64, steps []PushdownStep) bool {
+		if _, exists := distances[state.state]; !exists && state.state >= 0 {
+			distances[state.state] = distance
+		}
+		return true
+	})
+	return distances
+}
+
+// pushdownSearch visits the configurations reachable from the node in increasing cost order
+// (Dijkstra), until `visit` returns false
+// NOTE: the paths are made of cards, the transitive cards passed through included
+func (g *MystGraph) pushdownSearch(
+	view *stateGraph,
+	from int64,
+	context []int64,
+	visit func(state pushdownState, distance float64, path []int64, steps []PushdownStep) bool,
+) {
+	type entry struct {
+		state    pushdownState
+		parent   int32
+		step     PushdownStep
+		distance float64
+	}
+
+	start := pushdownState{state: from, depth: len(context)}
+	entries := []entry{{state: start, parent: -1}}
+	best := map[pushdownState]float64{start: 0}
+	settled := make(map[pushdownState]bool)
+
+	// rebuild the path leading to an entry
+	pathTo := func(i int32) ([]int64, []PushdownStep) {
+		var path []int64
+		var steps []PushdownStep
+		for ; i >= 0; i = entries[i].parent {
+			path = append(path, view.card(entries[i].state.state))
+			if entries[i].parent >= 0 {
+				steps = append(steps, entries[i].step)
+			}
+		}
+		slices.Reverse(path)
+		slices.Reverse(steps)
+		return path, steps
+	}
+
+	// NOTE: the queue items are the entries (ties broken by insertion order, for determinism)
+	queue := &stateQueue{{state: 0}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(stateQueueItem)
+		current := entries[item.state]
+		if settled[current.state] {
+			continue // outdated item
+		}
+		settled[current.state] = true
+
+		path, steps := pathTo(item.state)
+		if !visit(current.state, current.distance, path, steps) {
+			return
+		}
+
+		enqueue := func(next pushdownState, step PushdownStep, cost float64) {
+			distance := current.distance + cost
+			if previous, exists := best[next]; settled[next] || (exists && previous <= distance) {
+				return
+			}
+			best[next] = distance
+			entries = append(entries, entry{state: next, parent: item.state, step: step, distance: distance})
+			heap.Push(queue, stateQueueItem{state: int32(len(entries) - 1), distance: distance})
+		}
+
+		state := current.state
+		card := view.card(state.state)
+		passing := state.state < 0 // transitive card passed through
+
+		// return to the last card of the context
+		// NOTE: returning to a card pushed during the search is useless (see pushdownState)
+		if !passing && g.nodeIsOfType(card, common.PopsCard) && !state.pushed && state.depth > 0 {
+			target := context[state.depth-1]
+			if cost := g.popCost(card, target); !math.IsInf(cost, 1) {
+				enqueue(pushdownState{state: target, depth: state.depth - 1}, PopStep, cost)
+			}
+		}
+
+		pushes := !passing && g.nodeIsOfType(card, common.PushesCard)
+		for _, a := range sortedArcs(view, state.state) {
+			if pushes {
+				enqueue(pushdownState{state: a.id, depth: state.depth, pushed: true}, PushStep, a.weight)
+			} else {
+				enqueue(pushdownState{state: a.id, depth: state.depth, pushed: state.pushed}, InternalStep, a.weight)
+			}
+		}
+	}
+}
+
+// stateArc is an edge of the state graph
+type stateArc struct {
+	id     int64
+	weight float64
+}
+
+// sortedArcs returns the edges from a state, sorted by target (for determinism)
+func sortedArcs(view *stateGraph, id int64) []stateArc {
+	var arcs []stateArc
+	successors := view.From(id)
+	for successors.Next() {
+		toID := successors.Node().ID()
+		arcs = append(arcs, stateArc{id: toID, weight: view.WeightedEdge(id, toID).Weight()})
+	}
+	slices.SortFunc(arcs, func(a, b stateArc) int {
+		return cmp.Compare(a.id, b.id)
+	})
+	return arcs
+}
+
+// popCost returns the cost of the return from a card to the last pushed card (`pop card`),
+// weighted as a link between the two cards
+func (g *MystGraph) popCost(from, to int64) float64 {
+	source, target := g.NodeMap[g.IdNameMap[from]], g.NodeMap[g.IdNameMap[to]]
+
+	edge := common.Edge{Source: &source, Target: &target, Attributes: []common.EdgeAttribute{common.IntraAge}}
+	if source.StackName != target.StackName {
+		edge.Attributes = []common.EdgeAttribute{common.CrossAge}
+	}
+
+	return g.CostModel.Cost(&edge)
+}
+
+// pushdownSuccessors returns the sorted targets of the links from the node
+// (self-loops and disabled links excepted)
+func (g *MystGraph) pushdownSuccessors(id int64) []int64 {
+	var successors []int64
+	for toID, edges := range g.EdgeMap[id] {
+		if toID == id {
+			continue
+		}
+		for _, edge := range edges {
+			if !edge.IsOfType(common.Disabled) {
+				successors = append(successors, toID)
+				break
+			}
+		}
+	}
+	slices.Sort(successors)
+	return successors
+}
+
+// ComputePushdownSummaries summarizes the excursions from each card pushing itself:
+// the cards reachable from it before any nested push, and those returning to it
+func (g *MystGraph) ComputePushdownSummaries() []common.PushdownSummary {
+	var pushers []int64
+	for id := range g.IdNameMap {
+		if g.nodeIsOfType(id, common.PushesCard) {
+			pushers = append(pushers, id)
+		}
+	}
+	slices.Sort(pushers)
+
+	var summaries []common.PushdownSummary
+	for _, pusher := range pushers {
+		summary := common.PushdownSummary{Pusher: g.nodeInfo(pusher)}
+
+		// same-level region: the cards pushing themselves are not expanded
+		// (their excursions return to them)
+		visited := map[int64]bool{pusher: true}
+		queue := slices.Clone(g.pushdownSuccessors(pusher))
+		for _, id := range queue {
+			visited[id] = true
+		}
+
+		for len(queue) > 0 {
+			id := queue[0]
+			queue = queue[1:]
+
+			summary.Excursion = append(summary.Excursion, g.nodeInfo(id))
+			if g.nodeIsOfType(id, common.PopsCard) {
+				summary.Poppers = append(summary.Poppers, g.nodeInfo(id))
+			}
+
+			if g.nodeIsOfType(id, common.PushesCard) {
+				continue
+			}
+
+			for _, toID := range g.pushdownSuccessors(id) {
+				if !visited[toID] {
+					visited[toID] = true
+					queue = append(queue, toID)
+				}
+			}
+		}
+
+		sortNodeInfos(summary.Excursion)
+		sortNodeInfos(summary.Poppers)
+		summaries = append(summaries, summary)
+	}
+
+	return summaries
+}
+
+// ComputePushdownStats computes the push/pop summaries, and compares the nodes reachable
+// from the entry card under the push card / pop card discipline with those reachable
+// through the links the cost model can traverse
+func (g *MystGraph) ComputePushdownStats() common.PushdownStats {
+	stats := common.PushdownStats{Summaries: g.ComputePushdownSummaries()}
+
+	entryID, err := g.EntryNodeID()
+	if err != nil {
+		return stats
+	}
+
+	reachable := g.PushdownReachable(entryID, nil)
+	stats.Reachable = len(reachable)
+
+	traversable := g.reachableCards(entryID)
+	for id := range reachable {
+		if !traversable[id] {
+			stats.ReachableThroughPush = append(stats.ReachableThroughPush, g.nodeInfo(id))
+		}
+	}
+	sortNodeInfos(stats.ReachableThroughPush)
+
+	return stats
+}
+
+// nodeIsOfType reports whether the node has the given attribute
+func (g *MystGraph) nodeIsOfType(id int64, attribute common.NodeAttribute) bool {
+	node, exists := g.NodeMap[g.IdNameMap[id]]
+	return exists && node.IsOfType(attribute)
+}
+
+func (g *MystGraph) nodeInfo(id int64) common.NodeInfo {
+	return common.NodeInfo{ID: id, Name: g.GetNameForID(id)}
+}
@@ -0,0 +1,284 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// pushdownFixture: Myst:1 and Myst:2 push themselves, Myst:3 and Myst:101 pop, Myst:100 and
+// Myst:101 are the context; Myst:20 pops, but is only passed through (transitive card)
+func pushdownFixture() *fixture {
+	f := newFixture()
+	f.card("Myst:1", common.PushesCard)
+	f.card("Myst:2", common.PushesCard)
+	f.card("Myst:3", common.PopsCard)
+	f.card("Myst:100")
+	f.card("Myst:101", common.PopsCard)
+	f.card("Myst:20", common.PopsCard)
+
+	f.path("Myst:1", "Myst:2", "Myst:3", "Myst:4")
+	f.transitive("Myst:10", "Myst:20", "Myst:30", 1)
+	f.path("Myst:40", "Myst:20")
+	return f
+}
+
+func TestPushdownReachable(t *testing.T) {
+	f := pushdownFixture()
+	g := f.graph(t, "")
+
+	context := []int64{f.id("Myst:100"), f.id("Myst:101")}
+	tests := []struct {
+		name      string
+		from      string
+		context   []int64
+		reachable string
+	}{
+		// the pops return to the pushed cards (Myst:2, then Myst:1), never to the context
+		{"nested push and pop", "Myst:1", context, "Myst:1 Myst:2 Myst:3 Myst:4"},
+		{"pop to the context", "Myst:3", context, "Myst:3 Myst:4 Myst:100 Myst:101"},
+		{"pop with an empty context", "Myst:3", nil, "Myst:3 Myst:4"},
+		{"pop with an exhausted context", "Myst:3", context[:1], "Myst:3 Myst:4 Myst:100"},
+		// Myst:20 is passed through from Myst:10 (no pop), but reached from Myst:40
+		{"pop in a pending state", "Myst:10", context, "Myst:10 Myst:30"},
+		{"pop in a transitive card", "Myst:40", context, "Myst:20 Myst:40 Myst:100 Myst:101"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			distances := g.PushdownReachable(f.id(test.from), test.context)
+
+			ids := make([]int64, 0, len(distances))
+			for id := range distances {
+				ids = append(ids, id)
+			}
+			slices.Sort(ids)
+
+			if reachable := names(g, ids); reachable != test.reachable {
+				t.Errorf("got %q, expected %q", reachable, test.reachable)
+			}
+		})
+	}
+}
+
+func TestComputePushdownPath(t *testing.T) {
+	f := pushdownFixture()
+	g := f.graph(t, "")
+	context := []int64{f.id("Myst:100"), f.id("Myst:101")}
+
+	tests := []struct {
+		from, to string
+		path     string
+		steps    []PushdownStep
+	}{
+		{"Myst:1", "Myst:4", "Myst:1 Myst:2 Myst:3 Myst:4", []PushdownStep{PushStep, PushStep, InternalStep}},
+		{"Myst:3", "Myst:100", "Myst:3 Myst:101 Myst:100", []PushdownStep{PopStep, PopStep}},
+		{"Myst:10", "Myst:30", "Myst:10 Myst:20 Myst:30", []PushdownStep{InternalStep, InternalStep}},
+		{"Myst:40", "Myst:101", "Myst:40 Myst:20 Myst:101", []PushdownStep{InternalStep, PopStep}},
+		{"Myst:1", "Myst:101", "", nil},
+		{"Myst:10", "Myst:101", "", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.from+" -> "+test.to, func(t *testing.T) {
+			result, err := g.ComputePushdownPath(f.id(test.from), f.id(test.to), context)
+			if test.path == "" {
+				if err == nil {
+					t.Errorf("expected no path, got %q", names(g, result.Path))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if path := names(g, result.Path); path != test.path || !slices.Equal(result.Steps, test.steps) {
+				t.Errorf("got %q %v, expected %q %v", path, result.Steps, test.path, test.steps)
+			}
+			if result.Distance != float64(len(test.steps)) {
+				t.Errorf("got the distance %v, expected %d", result.Distance, len(test.steps))
+			}
+		})
+	}
+}
+
+// stackState is a configuration of the reference pushdown search: the whole stack is recorded
+type stackState struct {
+	state int64
+	stack string // pushed cards (indexes in the stack cards), from bottom to top
+}
+
+// referencePushdownReachable returns the cost of the path to each node, recording the whole stack,
+// to check the reduction of pushdownSearch (see pushdownState)
+// NOTE: the pushes beyond the context are bounded by the number of cards pushing themselves: the
+// pops after a push return to visited configurations, so the shortest paths push along paths
+// without repeated states
+func referencePushdownReachable(g *MystGraph, from int64, context []int64) map[int64]float64 {
+	view := g.pathAnalyzer.pushdownGraph()
+
+	maxDepth := len(context)
+	for id := range g.IdNameMap {
+		if g.nodeIsOfType(id, common.PushesCard) {
+			maxDepth++
+		}
+	}
+
+	var cards []int64 // cards of the stacks, indexed by the stack bytes
+	encode := func(stack []int64) string {
+		encoded := make([]byte, len(stack))
+		for i, id := range stack {
+			index := slices.Index(cards, id)
+			if index < 0 {
+				index = len(cards)
+				cards = append(cards, id)
+			}
+			encoded[i] = byte(index)
+		}
+		return string(encoded)
+	}
+
+	var states []stackState
+	index := make(map[stackState]int32)
+	best := make(map[int32]float64)
+	queue := &stateQueue{}
+	push := func(next stackState, distance float64) {
+		i, exists := index[next]
+		if !exists {
+			i = int32(len(states))
+			index[next] = i
+			states = append(states, next)
+		} else if best[i] <= distance {
+			return
+		}
+		best[i] = distance
+		heap.Push(queue, stateQueueItem{state: i, distance: distance})
+	}
+	push(stackState{state: from, stack: encode(context)}, 0)
+
+	distances := make(map[int64]float64)
+	settled := make(map[int32]bool)
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(stateQueueItem)
+		if settled[item.state] {
+			continue
+		}
+		settled[item.state] = true
+
+		current := states[item.state]
+		if _, exists := distances[current.state]; !exists && current.state >= 0 {
+			distances[current.state] = item.distance
+		}
+
+		passing := current.state < 0
+		card := view.card(current.state)
+
+		if depth := len(current.stack); !passing && g.nodeIsOfType(card, common.PopsCard) && depth > 0 {
+			target := cards[current.stack[depth-1]]
+			if cost := g.popCost(card, target); !math.IsInf(cost, 1) {
+				push(stackState{state: target, stack: current.stack[:depth-1]}, item.distance+cost)
+			}
+		}
+
+		next := current.stack
+		if !passing && g.nodeIsOfType(card, common.PushesCard) {
+			if len(current.stack) >= maxDepth {
+				continue
+			}
+			next += encode([]int64{card})
+		}
+		for _, a := range sortedArcs(view, current.state) {
+			push(stackState{state: a.id, stack: next}, item.distance+a.weight)
+		}
+	}
+
+	return distances
+}
+
+func TestPushdownReduction(t *testing.T) {
+	for seed := range int64(200) {
+		f := randomFixture(seed, 6, 0.3)
+
+		// random pushing and popping cards, and a context of existing cards
+		r := rand.New(rand.NewSource(seed))
+		var cards []string
+		for name := range f.nodes {
+			cards = append(cards, name)
+		}
+		slices.Sort(cards)
+		for _, name := range cards {
+			switch r.Intn(4) {
+			case 0:
+				f.card(name, common.PushesCard)
+			case 1:
+				f.card(name, common.PopsCard)
+			case 2:
+				f.card(name, common.PushesCard, common.PopsCard)
+			}
+		}
+
+		g := f.graph(t, "")
+		var context []int64
+		for range r.Intn(4) {
+			context = append(context, f.id(cards[r.Intn(len(cards))]))
+		}
+
+		for _, from := range cards {
+			distances := g.PushdownReachable(f.id(from), context)
+			expected := referencePushdownReachable(g, f.id(from), context)
+
+			if len(distances) != len(expected) {
+				t.Errorf("seed %d, from %s: %d reachable nodes, expected %d", seed, from, len(distances), len(expected))
+			}
+			for id, distance := range expected {
+				if got, exists := distances[id]; !exists || got != distance {
+					t.Errorf("seed %d, from %s: %s at %v, expected %v", seed, from, g.GetNameForID(id), got, distance)
+				}
+			}
+		}
+	}
+}
+
+func TestComputePushdownSummaries(t *testing.T) {
+	f := newFixture()
+	f.card("Myst:1", common.PushesCard)
+	f.card("Myst:5", common.PushesCard)
+	f.card("Myst:3", common.PopsCard)
+	f.card("Myst:6", common.PopsCard)
+
+	// Myst:1 -> Myst:2 -> Myst:3 (pops) and Myst:2 -> Myst:5 (pushes) -> Myst:6 (pops)
+	f.path("Myst:1", "Myst:2", "Myst:3")
+	f.path("Myst:2", "Myst:5", "Myst:6")
+	f.link("Myst:3", "Myst:4", common.Disabled)
+	g := f.graph(t, "")
+
+	summaries := g.ComputePushdownSummaries()
+	if len(summaries) != 2 {
+		t.Fatalf("got %d summaries, expected 2", len(summaries))
+	}
+
+	infoNames := func(nodes []common.NodeInfo) string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, node.Name)
+		}
+		return strings.Join(result, " ")
+	}
+
+	// the excursion from Myst:1 stops at Myst:5 (nested push), and skips the disabled link
+	expected := []struct{ pusher, excursion, poppers string }{
+		{"Myst:1", "Myst:2 Myst:3 Myst:5", "Myst:3"},
+		{"Myst:5", "Myst:6", "Myst:6"},
+	}
+	for i, summary := range summaries {
+		if summary.Pusher.Name != expected[i].pusher || infoNames(summary.Excursion) != expected[i].excursion || infoNames(summary.Poppers) != expected[i].poppers {
+			t.Errorf("got %s: %q (poppers: %q), expected %s: %q (poppers: %q)",
+				summary.Pusher.Name, infoNames(summary.Excursion), infoNames(summary.Poppers),
+				expected[i].pusher, expected[i].excursion, expected[i].poppers)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"encoding/csv"
+	"io"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// ComputeAgeQuotient collapses the cards by stack: each link between two stacks
+// counts the edges between their cards, with their attributes, exit, and entry cards
+// NOTE: the disabled edges are counted (see the `Disabled` attribute count)
+func (g *MystGraph) ComputeAgeQuotient() common.AgeQuotient {
+	quotient := common.AgeQuotient{Cards: make(map[string]int)}
+
+	for id := range g.IdNameMap {
+		stack := g.StackOf(id)
+		if quotient.Cards[stack] == 0 {
+			quotient.Stacks = append(quotient.Stacks, stack)
+		}
+		quotient.Cards[stack]++
+	}
+	sort.Strings(quotient.Stacks)
+
+	links := make(map[[2]string]*common.AgeLink)
+	for _, edge := range g.Metadata.Edges {
+		from, to := g.StackOf(edge.Source.GraphID), g.StackOf(edge.Target.GraphID)
+		if from == to {
+			continue
+		}
+
+		key := [2]string{from, to}
+		link, exists := links[key]
+		if !exists {
+			link = &common.AgeLink{From: from, To: to, AttributeCounts: make(map[string]int)}
+			links[key] = link
+		}
+
+		link.Count++
+		for _, attr := range edge.Attributes {
+			link.AttributeCounts[attr.String()]++
+		}
+
+		exit := common.NodeInfo{ID: edge.Source.GraphID, Name: edge.Source.Name}
+		if !slices.Contains(link.ExitCards, exit) {
+			link.ExitCards = append(link.ExitCards, exit)
+		}
+
+		entry := common.NodeInfo{ID: edge.Target.GraphID, Name: edge.Target.Name}
+		if !slices.Contains(link.EntryCards, entry) {
+			link.EntryCards = append(link.EntryCards, entry)
+		}
+	}
+
+	for _, link := range links {
+		sortNodeInfos(link.ExitCards)
+		sortNodeInfos(link.EntryCards)
+		quotient.Links = append(quotient.Links, *link)
+	}
+
+	sort.Slice(quotient.Links, func(i, j int) bool {
+		if quotient.Links[i].From != quotient.Links[j].From {
+			return quotient.Links[i].From < quotient.Links[j].From
+		}
+		return quotient.Links[i].To < quotient.Links[j].To
+	})
+
+	return quotient
+}
+
+// WriteAgeMatrix saves the cross-age transition matrix as CSV
+// (the number of links from each stack, in rows, to each stack, in columns)
+func (g *MystGraph) WriteAgeMatrix(w io.Writer) error {
+	quotient := g.Metadata.Stats.AgeQuotient
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(append([]string{"from \\ to"}, quotient.Stacks...)); err != nil {
+		return err
+	}
+
+	for i, row := range quotient.Matrix() {
+		record := []string{quotient.Stacks[i]}
+		for _, count := range row {
+			record = append(record, strconv.Itoa(count))
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func sortNodeInfos(nodes []common.NodeInfo) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].Name < nodes[j].Name
+	})
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+func TestComputeAgeQuotient(t *testing.T) {
+	f := newFixture()
+	f.path("Myst:1", "Myst:2")
+	f.path("Myst:1", "Channelwood:10", "Myst:1")
+	f.link("Myst:2", "Channelwood:10", common.Disabled)
+	f.path("Myst:1", "Channelwood:11", "Selenitic:20")
+	g := f.graph(t, "")
+
+	quotient := g.ComputeAgeQuotient()
+	g.Metadata.Stats.AgeQuotient = quotient
+
+	if !slices.Equal(quotient.Stacks, []string{"Channelwood", "Myst", "Selenitic"}) {
+		t.Errorf("got the stacks %q", quotient.Stacks)
+	}
+	if cards := map[string]int{"Channelwood": 2, "Myst": 2, "Selenitic": 1}; !maps.Equal(quotient.Cards, cards) {
+		t.Errorf("got the cards %v, expected %v", quotient.Cards, cards)
+	}
+
+	nodeNames := func(nodes []common.NodeInfo) string {
+		var ids []int64
+		for _, node := range nodes {
+			ids = append(ids, node.ID)
+		}
+		return names(g, ids)
+	}
+
+	var links []string
+	for _, link := range quotient.Links {
+		links = append(links, fmt.Sprintf("%s -> %s: %d %v [%s] [%s]",
+			link.From, link.To, link.Count, link.AttributeCounts, nodeNames(link.ExitCards), nodeNames(link.EntryCards)))
+	}
+	// NOTE: the links inside a stack are ignored, the disabled ones are counted
+	expected := []string{
+		"Channelwood -> Myst: 1 map[CrossAge:1] [Channelwood:10] [Myst:1]",
+		"Channelwood -> Selenitic: 1 map[CrossAge:1] [Channelwood:11] [Selenitic:20]",
+		"Myst -> Channelwood: 3 map[CrossAge:3 Disabled:1] [Myst:1 Myst:2] [Channelwood:10 Channelwood:11]",
+	}
+	if !slices.Equal(links, expected) {
+		t.Errorf("got the links:\n%q\nexpected:\n%q", links, expected)
+	}
+
+	matrix := quotient.Matrix()
+	if expected := [][]int{{0, 1, 1}, {3, 0, 0}, {0, 0, 0}}; !slices.EqualFunc(matrix, expected, slices.Equal) {
+		t.Errorf("got the matrix %v, expected %v", matrix, expected)
+	}
+
+	var csv bytes.Buffer
+	if err := g.WriteAgeMatrix(&csv); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expectedCSV := "from \\ to,Channelwood,Myst,Selenitic\nChannelwood,0,1,1\nMyst,3,0,0\nSelenitic,0,0,0\n"
+	if csv.String() != expectedCSV {
+		t.Errorf("got the CSV:\n%s\nexpected:\n%s", csv.String(), expectedCSV)
+	}
+}
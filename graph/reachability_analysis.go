@@ -0,0 +1,266 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// maxExplanationNames is the number of cards named in an explanation
+const maxExplanationNames = 3
+
+// pushdownTree contains the cards reached by a pushdown search (see pushdownSearch)
+type pushdownTree struct {
+	paths  map[int64][]int64 // card -> first path (lowest cost), transitive cards passed through included
+	stacks map[int64][]int64 // card (not passed through) -> cards pushed when first reached
+}
+
+// searchPushdownTree returns the cards reached from the given card, without the cards rejected
+// by the filter (if any)
+// NOTE: the filtered cards (e.g., the virtual ones) have no links, hence are never passed through
+func (g *MystGraph) searchPushdownTree(view *stateGraph, root int64, filter func(id int64) bool) *pushdownTree {
+	tree := &pushdownTree{
+		paths:  make(map[int64][]int64),
+		stacks: make(map[int64][]int64),
+	}
+
+	g.pushdownSearch(view, root, nil, func(state pushdownState, distance float64, path []int64, steps []PushdownStep) bool {
+		card := view.card(state.state)
+		if filter != nil && !filter(card) {
+			return true
+		}
+
+		if _, exists := tree.paths[card]; !exists {
+			tree.paths[card] = path
+		}
+		if _, exists := tree.stacks[card]; !exists && state.state >= 0 {
+			tree.stacks[card] = pushedCards(path, steps)
+		}
+		return true
+	})
+
+	return tree
+}
+
+// pushedCards returns the cards pushed along a path (from bottom to top)
+func pushedCards(path []int64, steps []PushdownStep) []int64 {
+	stack := []int64{}
+	for i, step := range steps {
+		switch step {
+		case PushStep:
+			stack = append(stack, path[i])
+		case PopStep:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return stack
+}
+
+// reaches reports whether the card is reached
+func (t *pushdownTree) reaches(card int64) bool {
+	_, exists := t.paths[card]
+	return exists
+}
+
+// path returns the cards of the first path from the root to the given card
+func (t *pushdownTree) path(card int64) []int64 {
+	return t.paths[card]
+}
+
+// ComputeReachability analyzes the reachability of the cards from the entry card, under the
+// push card / pop card discipline (see ComputePushdownPath): the cards unreachable from it,
+// the reachable cards from which no goal card can be reached (soft-locks), and the cards
+// reachable only through disabled links or virtual cards
+func (g *MystGraph) ComputeReachability() common.ReachabilityStats {
+	entryID, err := g.EntryNodeID()
+	if err != nil {
+		return common.ReachabilityStats{}
+	}
+
+	stats := common.ReachabilityStats{Entry: g.nodeInfo(entryID)}
+
+	// NOTE: the virtual cards do not exist, hence the paths cannot reach them
+	view := g.pathAnalyzer.pushdownGraph()
+	reachable := g.searchPushdownTree(view, entryID, func(id int64) bool {
+		return !g.nodeIsOfType(id, common.IsVirtual)
+	})
+	withCut := g.searchPushdownTree(g.buildStateGraph(common.Disabled, common.Backtracking), entryID, nil)
+
+	for _, id := range g.sortedCardIDs() {
+		switch {
+		case reachable.reaches(id):
+			continue
+		case withCut.reaches(id):
+			path := withCut.path(id)
+			g.addNodeAttribute(id, common.IsCutOnly)
+			stats.CutOnly = append(stats.CutOnly, common.ReachabilityInfo{
+				Node:        g.nodeInfo(id),
+				Path:        path,
+				Explanation: g.explainCutOnly(path, reachable),
+			})
+		case !g.nodeIsOfType(id, common.IsVirtual):
+			g.addNodeAttribute(id, common.IsUnreachable)
+			stats.Unreachable = append(stats.Unreachable, common.ReachabilityInfo{
+				Node:        g.nodeInfo(id),
+				Explanation: g.explainUnreachable(id, reachable, withCut),
+			})
+		}
+	}
+
+	goalIDs := g.GoalNodeIDs()
+	if len(goalIDs) == 0 {
+		return stats
+	}
+
+	// NOTE: the goal cards reachable without returning to a pushed card
+	leadsToGoal := g.statesLeadingTo(view, goalIDs)
+	for _, id := range g.sortedCardIDs() {
+		// NOTE: the card itself (not passed through as a transitive card)
+		stack, reached := reachable.stacks[id]
+		if !reached || leadsToGoal[id] || g.leadsToGoalThroughPop(view, id, stack, goalIDs) {
+			continue
+		}
+
+		explanation := "its links only lead to cards from which no goal card can be reached"
+		if view.From(id).Len() == 0 && (len(stack) == 0 || !g.nodeIsOfType(id, common.PopsCard)) {
+			explanation = "it has no traversable link"
+		}
+
+		g.addNodeAttribute(id, common.IsSoftLock)
+		stats.SoftLocks = append(stats.SoftLocks, common.ReachabilityInfo{
+			Node:        g.nodeInfo(id),
+			Path:        reachable.path(id),
+			Explanation: explanation,
+		})
+	}
+
+	return stats
+}
+
+// leadsToGoalThroughPop reports whether a goal card can be reached from the card, the given cards
+// being pushed (`pop card` returning to them)
+// NOTE: the cards pushed on the first path to the card (see pushdownTree), other paths
+// pushing other cards are not considered
+func (g *MystGraph) leadsToGoalThroughPop(view *stateGraph, id int64, stack []int64, goalIDs []int64) bool {
+	if len(stack) == 0 {
+		return false
+	}
+
+	found := false
+	g.pushdownSearch(view, id, stack, func(state pushdownState, distance float64, path []int64, steps []PushdownStep) bool {
+		found = slices.Contains(goalIDs, state.state)
+		return !found
+	})
+	return found
+}
+
+// statesLeadingTo returns the states from which one of the given cards can be reached
+func (g *MystGraph) statesLeadingTo(view *stateGraph, cards []int64) map[int64]bool {
+	leads := make(map[int64]bool)
+
+	var queue []int64
+	nodes := view.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		if slices.Contains(cards, view.card(id)) {
+			leads[id] = true
+			queue = append(queue, id)
+		}
+	}
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+
+		predecessors := view.To(id)
+		for predecessors.Next() {
+			fromID := predecessors.Node().ID()
+			if !leads[fromID] {
+				leads[fromID] = true
+				queue = append(queue, fromID)
+			}
+		}
+	}
+
+	return leads
+}
+
+// sortedCardIDs returns the IDs of the cards (virtual cards included), sorted by name
+func (g *MystGraph) sortedCardIDs() []int64 {
+	var ids []int64
+	for id := range g.IdNameMap {
+		if g.nodeIsOfType(id, common.IsCard) {
+			ids = append(ids, id)
+		}
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return g.GetNameForID(ids[i]) < g.GetNameForID(ids[j])
+	})
+
+	return ids
+}
+
+// explainCutOnly names the first link of the witness path leaving the reachable cards
+func (g *MystGraph) explainCutOnly(path []int64, reachable *pushdownTree) string {
+	for i := 1; i < len(path); i++ {
+		if reachable.reaches(path[i]) {
+			continue
+		}
+
+		from, to := g.GetNameForID(path[i-1]), g.GetNameForID(path[i])
+		edges := g.EdgeMap[path[i-1]][path[i]]
+		disabled := len(edges) > 0 && !slices.ContainsFunc(edges, func(edge common.Edge) bool {
+			return !edge.IsOfType(common.Disabled)
+		})
+
+		switch virtual := g.nodeIsOfType(path[i], common.IsVirtual); {
+		case disabled && virtual:
+			return fmt.Sprintf("through the disabled link %s -> %s, to a non-existent card", from, to)
+		case virtual:
+			return fmt.Sprintf("through the non-existent card %s (linked from %s)", to, from)
+		default:
+			return fmt.Sprintf("through the disabled link %s -> %s", from, to)
+		}
+	}
+
+	return "through disabled links"
+}
+
+// explainUnreachable explains why a card cannot be reached from the entry card,
+// from the cards linking to it
+func (g *MystGraph) explainUnreachable(id int64, reachable, withCut *pushdownTree) string {
+	var sources, reachedSources []string
+	for fromID, targets := range g.EdgeMap {
+		if fromID == id || len(targets[id]) == 0 {
+			continue
+		}
+
+		sources = append(sources, g.GetNameForID(fromID))
+		if reachable.reaches(fromID) || withCut.reaches(fromID) {
+			reachedSources = append(reachedSources, g.GetNameForID(fromID))
+		}
+	}
+
+	switch {
+	case len(sources) == 0:
+		return "no link leads to it"
+	case len(reachedSources) == 0:
+		return "only linked from unreachable cards: " + formatNames(sources)
+	default:
+		return "only linked through links that cannot be traversed (e.g., transitive links, or links with an infinite cost) from: " +
+			formatNames(reachedSources)
+	}
+}
+
+// formatNames lists the first names (sorted)
+func formatNames(names []string) string {
+	sort.Strings(names)
+	if len(names) > maxExplanationNames {
+		return fmt.Sprintf("%s, and %d more", strings.Join(names[:maxExplanationNames], ", "), len(names)-maxExplanationNames)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// reachabilityFixture: the entry card Myst:1 reaches the goal card Myst:9
+func reachabilityFixture() *fixture {
+	f := newFixture()
+	f.path("Myst:1", "Myst:2", "Myst:9")
+
+	// soft-locks: a dead end, and a loop
+	f.path("Myst:1", "Myst:5")
+	f.path("Myst:1", "Myst:6", "Myst:7", "Myst:6")
+
+	// cut content: a disabled link, and a non-existent card
+	f.link("Myst:2", "Myst:3", common.Disabled)
+	f.path("Myst:3", "Myst:9")
+	f.card("Myst:Ghost", common.IsVirtual)
+	f.link("Myst:2", "Myst:Ghost", common.NotImplemented)
+
+	// push card / pop card: Myst:31 returns to Myst:30, Myst:32 has no card to return to
+	f.card("Myst:30", common.PushesCard)
+	f.card("Myst:31", common.PopsCard)
+	f.card("Myst:32", common.PopsCard)
+	f.path("Myst:1", "Myst:30", "Myst:31")
+	f.path("Myst:30", "Myst:2")
+	f.path("Myst:1", "Myst:32")
+
+	// unreachable cards: Myst:61 is reached, but not through the Tail edge
+	f.path("Myst:50", "Myst:51")
+	f.transitive("Myst:60", "Myst:61", "Myst:62", 1)
+	f.path("Myst:1", "Myst:61")
+	return f
+}
+
+// formatReachability formats the cards with their witness paths and explanations
+func formatReachability(g *MystGraph, infos []common.ReachabilityInfo) []string {
+	var result []string
+	for _, info := range infos {
+		result = append(result, fmt.Sprintf("%s [%s] %s", info.Node.Name, names(g, info.Path), info.Explanation))
+	}
+	return result
+}
+
+func TestComputeReachability(t *testing.T) {
+	f := reachabilityFixture()
+	g := f.graph(t, "Myst:1", "Myst:9")
+	stats := g.ComputeReachability()
+
+	if stats.Entry.Name != "Myst:1" {
+		t.Errorf("got the entry card %s", stats.Entry.Name)
+	}
+
+	tests := []struct {
+		name      string
+		infos     []common.ReachabilityInfo
+		attribute common.NodeAttribute
+		expected  []string
+	}{
+		{"unreachable", stats.Unreachable, common.IsUnreachable, []string{
+			"Myst:50 [] no link leads to it",
+			"Myst:51 [] only linked from unreachable cards: Myst:50",
+			"Myst:60 [] no link leads to it",
+			"Myst:62 [] only linked through links that cannot be traversed (e.g., transitive links, or links with an infinite cost) from: Myst:61",
+		}},
+		{"soft-locks", stats.SoftLocks, common.IsSoftLock, []string{
+			"Myst:32 [Myst:1 Myst:32] it has no traversable link",
+			"Myst:5 [Myst:1 Myst:5] it has no traversable link",
+			"Myst:6 [Myst:1 Myst:6] its links only lead to cards from which no goal card can be reached",
+			"Myst:61 [Myst:1 Myst:61] it has no traversable link",
+			"Myst:7 [Myst:1 Myst:6 Myst:7] its links only lead to cards from which no goal card can be reached",
+		}},
+		{"cut only", stats.CutOnly, common.IsCutOnly, []string{
+			"Myst:3 [Myst:1 Myst:2 Myst:3] through the disabled link Myst:2 -> Myst:3",
+			"Myst:Ghost [Myst:1 Myst:2 Myst:Ghost] through the non-existent card Myst:Ghost (linked from Myst:2)",
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if infos := formatReachability(g, test.infos); !slices.Equal(infos, test.expected) {
+				t.Errorf("got:\n%s\nexpected:\n%s", strings.Join(infos, "\n"), strings.Join(test.expected, "\n"))
+			}
+
+			// the cards are marked
+			var marked []string
+			for _, node := range f.metadata.Nodes {
+				if node.IsOfType(test.attribute) {
+					marked = append(marked, node.Name)
+				}
+			}
+			var expected []string
+			for _, line := range test.expected {
+				name, _, _ := strings.Cut(line, " ")
+				expected = append(expected, name)
+			}
+			slices.Sort(marked)
+			slices.Sort(expected)
+			if !slices.Equal(marked, expected) {
+				t.Errorf("got the marked cards %q, expected %q", marked, expected)
+			}
+		})
+	}
+
+	// without goal cards, no soft-lock
+	g = reachabilityFixture().graph(t, "Myst:1")
+	if stats := g.ComputeReachability(); len(stats.SoftLocks) != 0 || len(stats.Unreachable) != 4 || len(stats.CutOnly) != 2 {
+		t.Errorf("got %d soft-locks, %d unreachable and %d cut-only cards without goal cards",
+			len(stats.SoftLocks), len(stats.Unreachable), len(stats.CutOnly))
+	}
+
+	// without an entry card, no analysis
+	g = reachabilityFixture().graph(t, "")
+	if stats := g.ComputeReachability(); stats.Entry.Name != "" || len(stats.Unreachable)+len(stats.SoftLocks)+len(stats.CutOnly) != 0 {
+		t.Errorf("got a reachability analysis without an entry card: %+v", stats)
+	}
+}
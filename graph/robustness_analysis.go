@@ -0,0 +1,312 @@
+package graph
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// FindBridges returns the node pairs whose edges, once all removed, disconnect the graph
+// (the edge directions are ignored), sorted by node IDs
+// NOTE: two nodes linked in both directions form a single undirected edge, so that a
+// stack reachable through a single (bidirectional) link is detected
+func (g *MystGraph) FindBridges() []common.EdgeGroupInfo {
+	bridges, _ := g.undirectedCutsAnalysis()
+	return bridges
+}
+
+// FindArticulationPoints returns the nodes whose removal disconnects the graph
+// (the edge directions are ignored), sorted by ID
+func (g *MystGraph) FindArticulationPoints() []common.NodeInfo {
+	_, articulationPoints := g.undirectedCutsAnalysis()
+	return articulationPoints
+}
+
+// undirectedCutsAnalysis finds the bridges and the articulation points (Tarjan's lowpoints)
+// on the underlying undirected simple graph
+func (g *MystGraph) undirectedCutsAnalysis() ([]common.EdgeGroupInfo, []common.NodeInfo) {
+	nodeIDs := g.traverser.getAllNodeIDs()
+
+	neighbors := make(map[int64][]int64, len(nodeIDs))
+	for _, id := range nodeIDs {
+		for _, neighborID := range g.traverser.getSortedNeighbors(id, true) {
+			if neighborID != id {
+				neighbors[id] = append(neighbors[id], neighborID)
+				neighbors[neighborID] = append(neighbors[neighborID], id)
+			}
+		}
+	}
+	for id := range neighbors {
+		slices.Sort(neighbors[id])
+		neighbors[id] = slices.Compact(neighbors[id])
+	}
+
+	order := make(map[int64]int, len(nodeIDs))
+	low := make(map[int64]int, len(nodeIDs))
+	isArticulation := make(map[int64]bool)
+	var bridges []common.EdgeGroupInfo
+	counter := 0
+
+	var visit func(id, parent int64)
+	visit = func(id, parent int64) {
+		counter++
+		order[id] = counter
+		low[id] = counter
+		children := 0
+
+		for _, neighborID := range neighbors[id] {
+			if neighborID == parent {
+				continue // simple graph: a single edge leads back to the parent
+			}
+
+			if _, visited := order[neighborID]; visited {
+				low[id] = min(low[id], order[neighborID])
+				continue
+			}
+
+			children++
+			visit(neighborID, id)
+			low[id] = min(low[id], low[neighborID])
+
+			if low[neighborID] > order[id] {
+				bridges = append(bridges, g.newEdgeGroupInfo(id, neighborID))
+			}
+			if parent != noParent && low[neighborID] >= order[id] {
+				isArticulation[id] = true
+			}
+		}
+
+		if parent == noParent && children > 1 {
+			isArticulation[id] = true
+		}
+	}
+
+	for _, id := range nodeIDs {
+		if _, visited := order[id]; !visited {
+			visit(id, noParent)
+		}
+	}
+
+	sort.Slice(bridges, func(i, j int) bool {
+		if bridges[i].From.ID != bridges[j].From.ID {
+			return bridges[i].From.ID < bridges[j].From.ID
+		}
+		return bridges[i].To.ID < bridges[j].To.ID
+	})
+
+	var articulationPoints []common.NodeInfo
+	for _, id := range nodeIDs {
+		if isArticulation[id] {
+			articulationPoints = append(articulationPoints, common.NodeInfo{ID: id, Name: g.GetNameForID(id)})
+		}
+	}
+
+	return bridges, articulationPoints
+}
+
+// noParent is the parent of the roots of the depth-first search
+// (node IDs are never negative, see StableNodeID)
+const noParent int64 = -1
+
+// newEdgeGroupInfo describes the edges between two nodes, in both directions
+// (the node with the lowest ID first)
+func (g *MystGraph) newEdgeGroupInfo(a, b int64) common.EdgeGroupInfo {
+	if a > b {
+		a, b = b, a
+	}
+
+	return common.EdgeGroupInfo{
+		From:  common.NodeInfo{ID: a, Name: g.GetNameForID(a)},
+		To:    common.NodeInfo{ID: b, Name: g.GetNameForID(b)},
+		Count: g.Graph.Lines(a, b).Len() + g.Graph.Lines(b, a).Len(),
+	}
+}
+
+// ComputeEdgeConnectivity returns the maximum number of edge-disjoint paths from the source
+// nodes to the target nodes (max-flow, Edmonds–Karp), and a minimum edge cut separating them
+// (Menger's theorem: both have the same size)
+// NOTE: each edge of the multigraph has a unit capacity (the links from a card to another
+// form a single edge, see AddEdge); the edges inside the source and target sets never
+// belong to the cut
+func (g *MystGraph) ComputeEdgeConnectivity(sources, targets []int64) (int, []common.EdgeGroupInfo) {
+	isSource := make(map[int64]bool, len(sources))
+	for _, id := range sources {
+		isSource[id] = true
+	}
+	isTarget := make(map[int64]bool, len(targets))
+	for _, id := range targets {
+		if !isSource[id] {
+			isTarget[id] = true
+		}
+	}
+
+	if len(isSource) == 0 || len(isTarget) == 0 {
+		return 0, nil
+	}
+
+	// residual capacities (the source and target sets are contracted into single nodes)
+	const source, sink int64 = -1, -2
+	contract := func(id int64) int64 {
+		if isSource[id] {
+			return source
+		}
+		if isTarget[id] {
+			return sink
+		}
+		return id
+	}
+
+	capacity := make(map[int64]map[int64]int)
+	addCapacity := func(from, to int64, c int) {
+		if capacity[from] == nil {
+			capacity[from] = make(map[int64]int)
+		}
+		if capacity[to] == nil {
+			capacity[to] = make(map[int64]int)
+		}
+		capacity[from][to] += c
+		capacity[to][from] += 0 // residual edge
+	}
+
+	edges := g.Graph.Edges()
+	for edges.Next() {
+		fromID, toID := edges.Edge().From().ID(), edges.Edge().To().ID()
+		from, to := contract(fromID), contract(toID)
+		if from == to || from == sink || to == source {
+			continue
+		}
+		addCapacity(from, to, g.Graph.Lines(fromID, toID).Len())
+	}
+
+	// sorted adjacency for deterministic augmenting paths
+	adjacency := make(map[int64][]int64, len(capacity))
+	for from, targets := range capacity {
+		for to := range targets {
+			adjacency[from] = append(adjacency[from], to)
+		}
+		slices.Sort(adjacency[from])
+	}
+
+	// breadth-first search of an augmenting path (or of the nodes reachable from the source)
+	bfs := func() map[int64]int64 {
+		parents := map[int64]int64{source: source}
+		queue := []int64{source}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range adjacency[current] {
+				if _, seen := parents[next]; seen || capacity[current][next] <= 0 {
+					continue
+				}
+				parents[next] = current
+				if next == sink {
+					return parents
+				}
+				queue = append(queue, next)
+			}
+		}
+		return parents
+	}
+
+	flow := 0
+	for {
+		parents := bfs()
+		if _, found := parents[sink]; !found {
+			// minimum cut: the edges from the nodes reachable from the source to the others
+			return flow, g.minimumCut(parents, isSource, isTarget)
+		}
+
+		// unit capacities: each augmenting path carries a single unit
+		for node := int64(sink); node != source; node = parents[node] {
+			capacity[parents[node]][node]--
+			capacity[node][parents[node]]++
+		}
+		flow++
+	}
+}
+
+// minimumCut returns the edges from the reachable side of the residual graph to the other side
+func (g *MystGraph) minimumCut(reachable map[int64]int64, isSource, isTarget map[int64]bool) []common.EdgeGroupInfo {
+	isReachable := func(id int64) bool {
+		if isSource[id] {
+			return true
+		}
+		if isTarget[id] {
+			return false
+		}
+		_, ok := reachable[id]
+		return ok
+	}
+
+	var cut []common.EdgeGroupInfo
+	edges := g.Graph.Edges()
+	for edges.Next() {
+		fromID, toID := edges.Edge().From().ID(), edges.Edge().To().ID()
+		if isReachable(fromID) && !isReachable(toID) {
+			cut = append(cut, common.EdgeGroupInfo{
+				From:  common.NodeInfo{ID: fromID, Name: g.GetNameForID(fromID)},
+				To:    common.NodeInfo{ID: toID, Name: g.GetNameForID(toID)},
+				Count: g.Graph.Lines(fromID, toID).Len(),
+			})
+		}
+	}
+
+	sort.Slice(cut, func(i, j int) bool {
+		if cut[i].From.ID != cut[j].From.ID {
+			return cut[i].From.ID < cut[j].From.ID
+		}
+		return cut[i].To.ID < cut[j].To.ID
+	})
+
+	return cut
+}
+
+// ComputeCardConnectivity returns the maximum number of edge-disjoint paths from a card
+// to another, and a minimum edge cut separating them
+func (g *MystGraph) ComputeCardConnectivity(from, to int64) (int, []common.EdgeGroupInfo) {
+	return g.ComputeEdgeConnectivity([]int64{from}, []int64{to})
+}
+
+// ComputeStackConnectivity computes the edge connectivity for each ordered pair of stacks
+func (g *MystGraph) ComputeStackConnectivity() []common.StackConnectivityInfo {
+	stackNodes := make(map[string][]int64)
+	for _, id := range g.traverser.getAllNodeIDs() {
+		stack := g.StackOf(id)
+		stackNodes[stack] = append(stackNodes[stack], id)
+	}
+
+	stacks := make([]string, 0, len(stackNodes))
+	for stack := range stackNodes {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	var result []common.StackConnectivityInfo
+	for _, from := range stacks {
+		for _, to := range stacks {
+			if from == to {
+				continue
+			}
+
+			connectivity, cut := g.ComputeEdgeConnectivity(stackNodes[from], stackNodes[to])
+			result = append(result, common.StackConnectivityInfo{
+				From:             from,
+				To:               to,
+				EdgeConnectivity: connectivity,
+				MinCut:           cut,
+			})
+		}
+	}
+
+	return result
+}
+
+// StackOf returns the name of the stack of a node
+// (stack nodes have no stack name: they are their own stack)
+func (g *MystGraph) StackOf(id int64) string {
+	if stack := g.IdStackMap[id]; stack != "" {
+		return stack
+	}
+	return g.GetNameForID(id)
+}
@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// robustnessFixture: two triangles of cards linked both ways (Myst and Channelwood),
+// joined by a single bidirectional link (Myst:3 and Channelwood:10, linked twice),
+// and a dead end in Selenitic, only left through a disabled link
+func robustnessFixture() *fixture {
+	f := newFixture()
+	for _, triangle := range [][]string{{"Myst:1", "Myst:2", "Myst:3"}, {"Channelwood:10", "Channelwood:11", "Channelwood:12"}} {
+		f.path(triangle[0], triangle[1], triangle[2], triangle[0])
+		f.path(triangle[0], triangle[2], triangle[1], triangle[0])
+	}
+	f.path("Myst:3", "Channelwood:10", "Myst:3")
+	f.link("Myst:3", "Channelwood:10")
+	f.path("Channelwood:12", "Selenitic:20")
+	f.link("Selenitic:20", "Myst:1", common.Disabled)
+	return f
+}
+
+func edgeGroups(groups []common.EdgeGroupInfo) []string {
+	var result []string
+	for _, group := range groups {
+		result = append(result, fmt.Sprintf("%s %s (%d)", group.From.Name, group.To.Name, group.Count))
+	}
+	slices.Sort(result)
+	return result
+}
+
+func TestUndirectedCuts(t *testing.T) {
+	f := robustnessFixture()
+	g := f.graph(t, "")
+
+	// NOTE: the bridges list the node with the lowest ID first
+	bridges := edgeGroups(g.FindBridges())
+	expected := []string{orderedPair(f, "Channelwood:12", "Selenitic:20") + " (1)", orderedPair(f, "Myst:3", "Channelwood:10") + " (2)"}
+	slices.Sort(expected)
+	if !slices.Equal(bridges, expected) {
+		t.Errorf("got the bridges %q, expected %q", bridges, expected)
+	}
+
+	var points []string
+	for _, point := range g.FindArticulationPoints() {
+		points = append(points, point.Name)
+	}
+	slices.Sort(points)
+	if expected := []string{"Channelwood:10", "Channelwood:12", "Myst:3"}; !slices.Equal(points, expected) {
+		t.Errorf("got the articulation points %q, expected %q", points, expected)
+	}
+}
+
+// orderedPair returns the names of two nodes, the one with the lowest ID first
+func orderedPair(f *fixture, a, b string) string {
+	if f.id(a) > f.id(b) {
+		a, b = b, a
+	}
+	return a + " " + b
+}
+
+func TestStackConnectivity(t *testing.T) {
+	g := robustnessFixture().graph(t, "")
+
+	var connectivity []string
+	for _, info := range g.ComputeStackConnectivity() {
+		connectivity = append(connectivity, fmt.Sprintf("%s -> %s: %d %q", info.From, info.To, info.EdgeConnectivity, edgeGroups(info.MinCut)))
+	}
+
+	// the parallel links from Myst:3 to Channelwood:10 form a single edge
+	expected := []string{
+		`Channelwood -> Myst: 1 ["Channelwood:10 Myst:3 (1)"]`,
+		`Channelwood -> Selenitic: 1 ["Channelwood:12 Selenitic:20 (1)"]`,
+		`Myst -> Channelwood: 1 ["Myst:3 Channelwood:10 (1)"]`,
+		`Myst -> Selenitic: 1 ["Myst:3 Channelwood:10 (1)"]`,
+		`Selenitic -> Channelwood: 0 []`,
+		`Selenitic -> Myst: 0 []`,
+	}
+	if !slices.Equal(connectivity, expected) {
+		t.Errorf("got:\n%q\nexpected:\n%q", connectivity, expected)
+	}
+
+	// two edge-disjoint paths in a triangle
+	f := robustnessFixture()
+	g = f.graph(t, "")
+	if count, cut := g.ComputeCardConnectivity(f.id("Myst:1"), f.id("Myst:2")); count != 2 || len(cut) != 2 {
+		t.Errorf("got the connectivity %d and the cut %q, expected 2 edges", count, edgeGroups(cut))
+	}
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/glthr/DeMystify/common"
+
+	"gonum.org/v1/gonum/graph/topo"
+)
+
+// FindStronglyConnectedComponents returns the strongly connected components of the graph
+// (Tarjan's algorithm) and the edges of their condensation DAG
+// NOTE: the disabled edges are not part of the graph, so they never connect components;
+// the components containing a goal node are not traps (the game legitimately ends there)
+func (g *MystGraph) FindStronglyConnectedComponents() ([]common.SCCInfo, []common.CondensationEdge) {
+	var components [][]int64
+	for _, sccNodes := range topo.TarjanSCC(g.Graph) {
+		component := make([]int64, 0, len(sccNodes))
+		for _, node := range sccNodes {
+			component = append(component, node.ID())
+		}
+		slices.Sort(component)
+		components = append(components, component)
+	}
+
+	// sort the components deterministically:
+	// 1. by size (largest first)
+	// 2. for equal sizes, by the smallest node ID
+	sort.Slice(components, func(i, j int) bool {
+		if len(components[i]) != len(components[j]) {
+			return len(components[i]) > len(components[j])
+		}
+		return components[i][0] < components[j][0]
+	})
+
+	sccs := make([]common.SCCInfo, len(components))
+	componentOf := make(map[int64]int, g.Graph.Nodes().Len())
+	for i, component := range components {
+		sccs[i] = common.SCCInfo{
+			ID:     i,
+			Nodes:  component,
+			Stacks: g.componentStacks(component),
+		}
+		for _, id := range component {
+			componentOf[id] = i
+		}
+	}
+
+	// condensation edges (each link is counted, the multigraph merging the links of a pair of nodes)
+	counts := make(map[[2]int]int)
+	for _, edge := range g.Metadata.Edges {
+		from, fromExists := componentOf[edge.Source.GraphID]
+		to, toExists := componentOf[edge.Target.GraphID]
+		if !fromExists || !toExists || edge.IsOfType(common.Disabled) {
+			continue
+		}
+		counts[[2]int{from, to}]++
+	}
+
+	var condensation []common.CondensationEdge
+	hasIncoming := make(map[int]bool)
+	hasOutgoing := make(map[int]bool)
+	for key, count := range counts {
+		if key[0] == key[1] {
+			continue // edge inside a component
+		}
+		condensation = append(condensation, common.CondensationEdge{From: key[0], To: key[1], Count: count})
+		hasOutgoing[key[0]] = true
+		hasIncoming[key[1]] = true
+	}
+
+	sort.Slice(condensation, func(i, j int) bool {
+		if condensation[i].From != condensation[j].From {
+			return condensation[i].From < condensation[j].From
+		}
+		return condensation[i].To < condensation[j].To
+	})
+
+	goals := make(map[int]bool)
+	for _, id := range g.GoalNodeIDs() {
+		goals[componentOf[id]] = true
+	}
+
+	for i := range sccs {
+		sccs[i].IsTrap = hasIncoming[i] && !hasOutgoing[i] && !goals[i]
+	}
+
+	return sccs, condensation
+}
+
+// componentStacks returns the sorted names of the stacks spanned by the nodes
+func (g *MystGraph) componentStacks(nodeIDs []int64) []string {
+	var stacks []string
+	for _, id := range nodeIDs {
+		stack := g.StackOf(id)
+		if !slices.Contains(stacks, stack) {
+			stacks = append(stacks, stack)
+		}
+	}
+
+	sort.Strings(stacks)
+	return stacks
+}
+
+// GetTrapComponents returns the strongly connected components that cannot be left
+func (g *MystGraph) GetTrapComponents() []common.SCCInfo {
+	var traps []common.SCCInfo
+	for _, scc := range g.Metadata.Stats.StronglyConnectedComponents {
+		if scc.IsTrap {
+			traps = append(traps, scc)
+		}
+	}
+	return traps
+}
@@ -0,0 +1,67 @@
+package graph
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+func TestSCCTraps(t *testing.T) {
+	f := newFixture()
+	f.path("Myst:1", "Myst:2", "Myst:1")
+
+	// one-way region of another Age (linked twice)
+	f.path("Myst:2", "Channelwood:10", "Channelwood:11", "Channelwood:10")
+	f.link("Myst:2", "Channelwood:10")
+	// only left through a disabled link
+	f.path("Myst:1", "Myst:20", "Myst:21", "Myst:20")
+	f.link("Myst:21", "Myst:1", common.Disabled)
+	// one-way region containing a goal card
+	f.path("Myst:2", "Myst:30", "Myst:31", "Myst:30")
+	// dead end, and a card only leading to the others
+	f.path("Myst:1", "Myst:40")
+	f.path("Myst:50", "Myst:1")
+	g := f.graph(t, "Myst:1", "Myst:31")
+
+	sccs, condensation := g.FindStronglyConnectedComponents()
+
+	var traps, others []string
+	componentOf := make(map[int]string)
+	for _, scc := range sccs {
+		component := names(g, scc.Nodes)
+		componentOf[scc.ID] = component
+		if scc.IsTrap {
+			traps = append(traps, component)
+		} else {
+			others = append(others, component)
+		}
+	}
+	slices.Sort(traps)
+	slices.Sort(others)
+
+	if expected := []string{"Channelwood:10 Channelwood:11", "Myst:20 Myst:21", "Myst:40"}; !slices.Equal(traps, expected) {
+		t.Errorf("got the traps %q, expected %q", traps, expected)
+	}
+	if expected := []string{"Myst:1 Myst:2", "Myst:30 Myst:31", "Myst:50"}; !slices.Equal(others, expected) {
+		t.Errorf("got the other components %q, expected %q", others, expected)
+	}
+
+	var edges []string
+	for _, edge := range condensation {
+		edges = append(edges, componentOf[edge.From]+" -> "+componentOf[edge.To]+": "+strings.Repeat("|", edge.Count))
+	}
+	slices.Sort(edges)
+
+	expected := []string{
+		"Myst:1 Myst:2 -> Channelwood:10 Channelwood:11: ||",
+		"Myst:1 Myst:2 -> Myst:20 Myst:21: |",
+		"Myst:1 Myst:2 -> Myst:30 Myst:31: |",
+		"Myst:1 Myst:2 -> Myst:40: |",
+		"Myst:50 -> Myst:1 Myst:2: |",
+	}
+	if !slices.Equal(edges, expected) {
+		t.Errorf("got the condensation edges:\n%s\nexpected:\n%s", strings.Join(edges, "\n"), strings.Join(expected, "\n"))
+	}
+}
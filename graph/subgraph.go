@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// Direction is the direction of the edges followed by the neighbourhood extraction
+type Direction string
+
+const (
+	OutgoingDirection Direction = "out"
+	IncomingDirection Direction = "in"
+	BothDirections    Direction = "both"
+)
+
+// ParseDirection returns the direction with the given name
+func ParseDirection(name string) (Direction, bool) {
+	direction := Direction(name)
+	switch direction {
+	case OutgoingDirection, IncomingDirection, BothDirections:
+		return direction, true
+	}
+	return "", false
+}
+
+// Subgraph is a subset of the nodes of the Myst Graph (with their original IDs),
+// and the edges crossing its boundary
+type Subgraph struct {
+	nodes map[int64]bool
+	// edges with a single end in the subgraph
+	BoundaryEdges []common.Edge
+}
+
+// Contains reports whether the node belongs to the subgraph
+func (s *Subgraph) Contains(id int64) bool {
+	return s.nodes[id]
+}
+
+// NodeIDs returns the sorted IDs of the nodes of the subgraph
+func (s *Subgraph) NodeIDs() []int64 {
+	ids := make([]int64, 0, len(s.nodes))
+	for id := range s.nodes {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// ExtractNeighbourhood returns the nodes at most `hops` edges away from the center
+// (ego graph), following the edges in the given direction
+// NOTE: all the edges are followed, including the disabled and backtracking ones,
+// as they are all rendered
+func (g *MystGraph) ExtractNeighbourhood(center int64, hops int, direction Direction) (*Subgraph, error) {
+	if _, exists := g.IdNameMap[center]; !exists {
+		return nil, fmt.Errorf("%w: %d", common.NodeNotFoundErr, center)
+	}
+
+	predecessors := make(map[int64][]int64)
+	for fromID, targets := range g.EdgeMap {
+		for toID := range targets {
+			predecessors[toID] = append(predecessors[toID], fromID)
+		}
+	}
+
+	nodes := map[int64]bool{center: true}
+	frontier := []int64{center}
+	for hop := 0; hop < hops && len(frontier) > 0; hop++ {
+		var next []int64
+		visit := func(id int64) {
+			if !nodes[id] {
+				nodes[id] = true
+				next = append(next, id)
+			}
+		}
+
+		for _, id := range frontier {
+			if direction != IncomingDirection {
+				for toID := range g.EdgeMap[id] {
+					visit(toID)
+				}
+			}
+			if direction != OutgoingDirection {
+				for _, fromID := range predecessors[id] {
+					visit(fromID)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return g.newSubgraph(nodes), nil
+}
+
+// ExtractStacks returns the nodes of the given stacks (including the stack nodes)
+func (g *MystGraph) ExtractStacks(stacks ...string) (*Subgraph, error) {
+	nodes := make(map[int64]bool)
+	for _, stack := range stacks {
+		if _, exists := g.NodeMap[stack]; !exists {
+			return nil, fmt.Errorf("%w: %s", common.NodeNotFoundErr, stack)
+		}
+	}
+
+	for id := range g.IdNameMap {
+		if slices.Contains(stacks, g.StackOf(id)) {
+			nodes[id] = true
+		}
+	}
+
+	return g.newSubgraph(nodes), nil
+}
+
+// newSubgraph creates the subgraph made of the given nodes and collects its boundary edges
+func (g *MystGraph) newSubgraph(nodes map[int64]bool) *Subgraph {
+	subgraph := &Subgraph{nodes: nodes}
+
+	for fromID, targets := range g.EdgeMap {
+		for toID, edges := range targets {
+			if nodes[fromID] != nodes[toID] {
+				subgraph.BoundaryEdges = append(subgraph.BoundaryEdges, edges...)
+			}
+		}
+	}
+
+	// deterministic order
+	slices.SortStableFunc(subgraph.BoundaryEdges, func(a, b common.Edge) int {
+		if c := cmp.Compare(a.Source.GraphID, b.Source.GraphID); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Target.GraphID, b.Target.GraphID)
+	})
+
+	return subgraph
+}
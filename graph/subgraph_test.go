@@ -0,0 +1,110 @@
+package graph
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+func subgraphFixture() *fixture {
+	f := newFixture()
+	for _, stack := range []string{"Myst", "Channelwood"} {
+		f.metadata.Nodes = append(f.metadata.Nodes, &common.Node{Name: stack, Attributes: []common.NodeAttribute{common.IsStack}})
+	}
+
+	f.path("Myst:1", "Myst:2", "Myst:3", "Myst:4")
+	f.path("Myst:5", "Myst:2")
+	f.link("Myst:3", "Channelwood:10", common.Disabled)
+	f.link("Channelwood:10", "Channelwood:11")
+	return f
+}
+
+// boundary formats the boundary edges of the subgraph, in their order
+func boundary(subgraph *Subgraph) []string {
+	var edges []string
+	for _, edge := range subgraph.BoundaryEdges {
+		edges = append(edges, fmt.Sprintf("%s %s", edge.Source.Name, edge.Target.Name))
+	}
+	return edges
+}
+
+func TestExtractNeighbourhood(t *testing.T) {
+	f := subgraphFixture()
+	g := f.graph(t, "")
+
+	tests := []struct {
+		hops      int
+		direction Direction
+		nodes     string
+	}{
+		{0, BothDirections, "Myst:2"},
+		{1, OutgoingDirection, "Myst:2 Myst:3"},
+		{1, IncomingDirection, "Myst:1 Myst:2 Myst:5"},
+		{1, BothDirections, "Myst:1 Myst:2 Myst:3 Myst:5"},
+		// the disabled edges are followed
+		{2, OutgoingDirection, "Channelwood:10 Myst:2 Myst:3 Myst:4"},
+		{5, IncomingDirection, "Myst:1 Myst:2 Myst:5"},
+		{5, BothDirections, "Channelwood:10 Channelwood:11 Myst:1 Myst:2 Myst:3 Myst:4 Myst:5"},
+	}
+
+	for _, test := range tests {
+		t.Run(fmt.Sprintf("%d %s", test.hops, test.direction), func(t *testing.T) {
+			subgraph, err := g.ExtractNeighbourhood(f.id("Myst:2"), test.hops, test.direction)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if nodes := sortedNames(g, subgraph.NodeIDs()); nodes != test.nodes {
+				t.Errorf("got %q, expected %q", nodes, test.nodes)
+			}
+			if !slices.IsSorted(subgraph.NodeIDs()) {
+				t.Errorf("the node IDs are not sorted")
+			}
+		})
+	}
+
+	// the boundary edges are sorted by source and target IDs
+	subgraph, _ := g.ExtractNeighbourhood(f.id("Myst:2"), 1, OutgoingDirection)
+	var expected []common.Edge
+	for _, edge := range f.metadata.Edges {
+		if subgraph.Contains(edge.Source.GraphID) != subgraph.Contains(edge.Target.GraphID) {
+			expected = append(expected, *edge)
+		}
+	}
+	slices.SortFunc(expected, func(a, b common.Edge) int {
+		return cmp.Or(cmp.Compare(a.Source.GraphID, b.Source.GraphID), cmp.Compare(a.Target.GraphID, b.Target.GraphID))
+	})
+	if edges := boundary(subgraph); len(edges) != 4 || !slices.Equal(edges, boundary(&Subgraph{BoundaryEdges: expected})) {
+		t.Errorf("got the boundary edges %q, expected %q", edges, boundary(&Subgraph{BoundaryEdges: expected}))
+	}
+
+	if _, err := g.ExtractNeighbourhood(f.id("Myst:1")+100, 1, BothDirections); !errors.Is(err, common.NodeNotFoundErr) {
+		t.Errorf("got the error %v for an unknown card, expected %v", err, common.NodeNotFoundErr)
+	}
+}
+
+func TestExtractStacks(t *testing.T) {
+	f := subgraphFixture()
+	g := f.graph(t, "")
+
+	subgraph, err := g.ExtractStacks("Channelwood")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nodes, expected := sortedNames(g, subgraph.NodeIDs()), "Channelwood Channelwood:10 Channelwood:11"; nodes != expected {
+		t.Errorf("got %q, expected %q", nodes, expected)
+	}
+	if !subgraph.Contains(f.id("Channelwood:10")) || subgraph.Contains(f.id("Myst:3")) {
+		t.Errorf("the subgraph does not contain the Channelwood cards only")
+	}
+	if edges := boundary(subgraph); !slices.Equal(edges, []string{"Myst:3 Channelwood:10"}) {
+		t.Errorf("got the boundary edges %q", edges)
+	}
+
+	if _, err := g.ExtractStacks("Myst", "Selenitic"); !errors.Is(err, common.NodeNotFoundErr) {
+		t.Errorf("got the error %v for an unknown stack, expected %v", err, common.NodeNotFoundErr)
+	}
+}
@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"math"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
+
+	"gonum.org/v1/gonum/graph/simple"
+)
+
+// traversableGraph returns a simple weighted view of the graph for the analyses that do not
+// support multigraphs: the parallel edges are merged (keeping the lowest cost), and the
+// self-loops, the backtracking and disabled edges, and the edges that cannot be traversed
+// (infinite cost, see CostModel) are dropped
+func (g *MystGraph) traversableGraph() *simple.WeightedDirectedGraph {
+	view := simple.NewWeightedDirectedGraph(0, math.Inf(1))
+
+	for id := range g.IdNameMap {
+		view.AddNode(simple.Node(id))
+	}
+
+	for fromID, targets := range g.EdgeMap {
+		for toID, edges := range targets {
+			if fromID == toID {
+				continue
+			}
+
+			weight := math.Inf(1)
+			for i := range edges {
+				if edges[i].IsOfType(common.Disabled) || edges[i].IsOfType(common.Backtracking) {
+					continue
+				}
+				weight = math.Min(weight, g.CostModel.Cost(&edges[i]))
+			}
+
+			if math.IsInf(weight, 1) {
+				continue
+			}
+
+			view.SetWeightedEdge(simple.WeightedEdge{
+				F: simple.Node(fromID),
+				T: simple.Node(toID),
+				W: weight,
+			})
+		}
+	}
+
+	return view
+}
+
+// stateGraph is the graph the paths are computed on: the restrictive transitivity edges
+// are expanded so that a Tail edge is always followed by the Head edge with the same
+// transitivity ID (the transitive card is only passed through)
+// NOTE: the card IDs identify the states reached through the other edges, and negative IDs
+// the states reached through a Tail edge (pending states)
+type stateGraph struct {
+	*simple.WeightedDirectedGraph
+	cards map[int64]int64 // pending state ID -> card ID
+}
+
+// transitivityKey identifies a pending state: the transitive card and the transitivity ID
+type transitivityKey struct {
+	card           int64
+	transitivityID int64
+}
+
+// transitivityGraph returns the state graph of the path analyses, weighted by the cost model
+// (the parallel edges are merged, keeping the lowest cost)
+// NOTE: the self-loops, the disabled edges, and the edges with an infinite cost are dropped
+func (g *MystGraph) transitivityGraph() *stateGraph {
+	return g.buildStateGraph()
+}
+
+// buildStateGraph returns the state graph, the edges with the ignored attributes (e.g., Disabled)
+// being weighted as if they did not have them
+func (g *MystGraph) buildStateGraph(ignored ...common.EdgeAttribute) *stateGraph {
+	view := &stateGraph{
+		WeightedDirectedGraph: simple.NewWeightedDirectedGraph(0, math.Inf(1)),
+		cards:                 make(map[int64]int64),
+	}
+
+	for id := range g.IdNameMap {
+		view.AddNode(simple.Node(id))
+	}
+
+	pending := make(map[transitivityKey]int64)
+	pendingState := func(card, transitivityID int64) int64 {
+		key := transitivityKey{card: card, transitivityID: transitivityID}
+		id, exists := pending[key]
+		if !exists {
+			id = -int64(len(pending) + 1)
+			pending[key] = id
+			view.cards[id] = card
+			view.AddNode(simple.Node(id))
+		}
+		return id
+	}
+
+	setEdge := func(fromID, toID int64, weight float64) {
+		if edge := view.WeightedEdge(fromID, toID); edge != nil && edge.Weight() <= weight {
+			return
+		}
+		view.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(fromID), T: simple.Node(toID), W: weight})
+	}
+
+	// sorted iteration, so that the pending state IDs are deterministic
+	fromIDs := make([]int64, 0, len(g.EdgeMap))
+	for fromID := range g.EdgeMap {
+		fromIDs = append(fromIDs, fromID)
+	}
+	slices.Sort(fromIDs)
+
+	for _, fromID := range fromIDs {
+		toIDs := make([]int64, 0, len(g.EdgeMap[fromID]))
+		for toID := range g.EdgeMap[fromID] {
+			toIDs = append(toIDs, toID)
+		}
+		slices.Sort(toIDs)
+
+		for _, toID := range toIDs {
+			if fromID == toID {
+				continue
+			}
+
+			for _, edge := range g.EdgeMap[fromID][toID] {
+				edge.Attributes = withoutAttributes(edge.Attributes, ignored)
+				if edge.IsOfType(common.Disabled) {
+					continue
+				}
+
+				weight := g.CostModel.Cost(&edge)
+				if math.IsInf(weight, 1) {
+					continue
+				}
+
+				switch {
+				case edge.IsOfType(common.RestrictiveTransitivityTail):
+					setEdge(fromID, pendingState(toID, edge.TransitivityID), weight)
+				case edge.IsOfType(common.RestrictiveTransitivityHead):
+					setEdge(pendingState(fromID, edge.TransitivityID), toID, weight)
+				default:
+					setEdge(fromID, toID, weight)
+				}
+			}
+		}
+	}
+
+	return view
+}
+
+// withoutAttributes returns the attributes, except the ignored ones
+func withoutAttributes(attributes, ignored []common.EdgeAttribute) []common.EdgeAttribute {
+	if len(ignored) == 0 {
+		return attributes
+	}
+	return slices.DeleteFunc(slices.Clone(attributes), func(attr common.EdgeAttribute) bool {
+		return slices.Contains(ignored, attr)
+	})
+}
+
+// card returns the card of a state
+func (s *stateGraph) card(id int64) int64 {
+	if card, exists := s.cards[id]; exists {
+		return card
+	}
+	return id
+}
+
+// addVisitStates adds a state per transitive card, reached from all its states (the card itself
+// and its pending states), and returns them by card: the states dominating it dominate
+// every visit of the card
+func (s *stateGraph) addVisitStates() map[int64]int64 {
+	pendingIDs := make([]int64, 0, len(s.cards))
+	for id := range s.cards {
+		pendingIDs = append(pendingIDs, id)
+	}
+	slices.Sort(pendingIDs)
+	slices.Reverse(pendingIDs) // -1, -2, ...
+
+	visits := make(map[int64]int64)
+	for _, pendingID := range pendingIDs {
+		card := s.cards[pendingID]
+		visit, exists := visits[card]
+		if !exists {
+			visit = -int64(len(s.cards) + len(visits) + 1)
+			visits[card] = visit
+			s.AddNode(simple.Node(visit))
+			s.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(card), T: simple.Node(visit)})
+		}
+		s.SetWeightedEdge(simple.WeightedEdge{F: simple.Node(pendingID), T: simple.Node(visit)})
+	}
+
+	return visits
+}
+
+// cardPath converts a path of states into a path of cards
+func (s *stateGraph) cardPath(states []int64) []int64 {
+	path := make([]int64, len(states))
+	for i, id := range states {
+		path[i] = s.card(id)
+	}
+	return path
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/glthr/DeMystify/cache"
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
+	"github.com/glthr/DeMystify/graph"
+	"github.com/glthr/DeMystify/parser"
+)
+
+// graphOptions are the flags shared by the commands loading the Myst Graph
+type graphOptions struct {
+	profileName     *string
+	rulesPath       *string
+	continueOnError *bool
+	costModel       *string
+	noCache         *bool
+
+	// reportSuffix distinguishes the reports of the corpora loaded by the same command (see runDiff)
+	reportSuffix string
+}
+
+// registerGraphFlags declares the flags shared by the commands loading the Myst Graph
+func registerGraphFlags(flags *flag.FlagSet) graphOptions {
+	return graphOptions{
+		profileName:     flags.String("profile", config.MystProfileName, "game profile: `myst`, `generic`, or the path to a JSON profile file"),
+		rulesPath:       flags.String("rules", "", "path to a JSON rules file declaring custom node and edge tags"),
+		continueOnError: flags.Bool("continue-on-error", false, "skip the malformed files and scripts, and report them in "+errorsFilePath),
+		noCache:         flags.Bool("no-cache", false, "parse and analyze the corpus again instead of loading the results of the previous run from "+cacheDirPath),
+		costModel:       flags.String("cost-model", "", "cost of the edges for the path analyses: `clicks` (default), age-switches, or backtracking=N (overrides the profile preset)"),
+	}
+}
+
+// reportPath inserts the suffix (if any) before the extension of a report path
+// (e.g., generated/errors-old.json)
+func reportPath(path, suffix string) string {
+	if suffix == "" {
+		return path
+	}
+
+	extension := filepath.Ext(path)
+	return strings.TrimSuffix(path, extension) + "-" + suffix + extension
+}
+
+// loadedGraph is the processed Myst Graph and the information gathered while loading it
+type loadedGraph struct {
+	profile     *config.Profile
+	deadLinks   *parser.DeadLinkReport
+	graph       *graph.MystGraph
+	costModel   graph.CostModel
+	metadata    *common.Metadata
+	parseErrors *common.ErrorReport
+}
+
+// loadGraph parses the stacks and cards, then generates and analyzes the Myst Graph
+func loadGraph(stacksDir string, options graphOptions, cycleOptions graph.CycleOptions) *loadedGraph {
+	profile, err := config.LoadProfile(*options.profileName)
+	if err != nil {
+		log.Fatalf("unable to load the profile: %v", err)
+	}
+
+	if *options.rulesPath != "" {
+		rules, err := config.ReadRules(*options.rulesPath)
+		if err != nil {
+			log.Fatalf("unable to load the rules: %v", err)
+		}
+
+		if err := profile.Rules.Extend(rules); err != nil {
+			log.Fatalf("unable to load the rules: %v", err)
+		}
+	}
+
+	if *options.costModel != "" {
+		if profile.CostModel == nil {
+			profile.CostModel = &config.CostModel{}
+		}
+		profile.CostModel.Preset = *options.costModel
+	}
+
+	costModel, err := graph.NewCostModel(profile.CostModel)
+	if err != nil {
+		log.Fatalf("unable to load the cost model: %v", err)
+	}
+
+	stages := openStageCache(stacksDir, *options.noCache)
+
+	// parser: extract information from the stacks and cards
+	parseKey := stages.key(profile.Rules.NodeRules, profile.Rules.EdgeRules, *options.continueOnError)
+	parsed, cached := stages.loadParse(parseKey)
+	if cached {
+		fmt.Printf("Loading the parsed stacks and cards from %s (profile: %s)...\n", cacheDirPath, profile.Name)
+	} else {
+		fmt.Printf("Parsing stacks and cards (profile: %s)...\n", profile.Name)
+		parsed = parseStacks(stacksDir, profile, *options.continueOnError)
+		stages.save(cache.ParseStage, parseKey, parsed)
+	}
+	metadata := parsed.Metadata
+
+	// errors skipped in the "continue on error" mode
+	parseErrors := parsed.ErrorReport()
+	if parseErrors.HasErrors() {
+		if err := WriteErrorReport(parseErrors, reportPath(errorsFilePath, options.reportSuffix)); err != nil {
+			log.Printf("unable to save the error report: %v", err)
+		}
+	}
+
+	// check the consistency between the stack card lists and the card files
+	if err := WriteIntegrityReport(parsed.Integrity, reportPath(integrityFilePath, options.reportSuffix)); err != nil {
+		log.Printf("unable to save the integrity report: %v", err)
+	}
+
+	// guard clause (helpful for external contributors...)
+	// NOTE: skipped files necessarily differ from the manifest, hence the warning only
+	if err := profile.Manifest.Check(metadata.TotalStacks, metadata.TotalCards); err != nil {
+		if !parseErrors.HasErrors() {
+			log.Fatalf("unexpected corpus for the %s profile: %v", profile.Name, err)
+		}
+		log.Printf("unexpected corpus for the %s profile: %v", profile.Name, err)
+	}
+
+	fmt.Printf("Stack count: %d\n", metadata.TotalStacks)
+	fmt.Printf("Cards count: %d\n", metadata.TotalCards)
+
+	// graph generation (and analyses)
+	// NOTE: the analyses are invalidated by the parsed corpus, and by the profile
+	// entry and goal cards, the cost model, and the cycle options
+	analysisKey := stages.key(parseKey, profile.EntryNode, profile.GoalNodes, profile.CostModel, cycleOptions)
+	analyzed, cached := stages.loadAnalysis(analysisKey)
+	if cached {
+		fmt.Printf("Loading the Myst Graph from %s...\n", cacheDirPath)
+		metadata = analyzed.Metadata
+	} else {
+		fmt.Println("Generating the Myst Graph...")
+	}
+
+	g, err := graph.NewGraph(metadata)
+	if err != nil {
+		log.Fatalf("error while instantiating the graph: %v", err)
+	}
+
+	g.CycleOptions = cycleOptions
+	g.SetCostModel(costModel)
+	if cached {
+		g.Restore(profile)
+	} else {
+		g.Process(profile)
+		stages.save(cache.AnalysisStage, analysisKey, &cache.AnalysisSnapshot{Metadata: metadata})
+	}
+
+	fmt.Printf("Nodes count: %d\n", metadata.TotalNodes)
+	fmt.Printf("Edges count: %d\n", metadata.TotalEdges)
+
+	return &loadedGraph{
+		profile:     profile,
+		deadLinks:   parsed.DeadLinks,
+		graph:       g,
+		costModel:   costModel,
+		metadata:    metadata,
+		parseErrors: parseErrors,
+	}
+}
+
+// parseStacks parses the stacks and cards, and gathers the reports of the corpus
+func parseStacks(stacksDir string, profile *config.Profile, continueOnError bool) *cache.ParseSnapshot {
+	p, err := parser.NewParser(stacksDir, parser.Options{
+		Rules:           profile.Rules,
+		ContinueOnError: continueOnError,
+	})
+	if err != nil {
+		log.Fatalf("unable to parse stacks and cards: %v", err)
+	}
+
+	metadata, err := p.Process()
+	if err != nil {
+		log.Fatalf("parser error: %v", err)
+	}
+
+	return &cache.ParseSnapshot{
+		Metadata:  metadata,
+		Errors:    p.Errors().Entries(),
+		Integrity: p.CheckIntegrity(),
+		DeadLinks: p.DeadLinks(),
+	}
+}
+
+// stageCache loads and saves the snapshots of the stages of a corpus (see the cache package)
+// NOTE: a nil stage cache (disabled cache) never hits
+type stageCache struct {
+	cache  *cache.Cache
+	corpus string // hash of the corpus path, naming its snapshots
+	inputs string // hash of the corpus files
+}
+
+// openStageCache returns the stage cache of a corpus, or nil if the cache is disabled or unusable
+func openStageCache(stacksDir string, disabled bool) *stageCache {
+	if disabled {
+		return nil
+	}
+
+	store, err := cache.New(cacheDirPath)
+	if err != nil {
+		log.Printf("cache disabled: %v", err)
+		return nil
+	}
+
+	inputs, err := cache.HashFiles(stacksDir)
+	if err != nil {
+		log.Printf("cache disabled: %v", err)
+		return nil
+	}
+
+	absolutePath, err := filepath.Abs(stacksDir)
+	if err != nil {
+		absolutePath = stacksDir
+	}
+	corpus := sha256.Sum256([]byte(absolutePath))
+
+	return &stageCache{
+		cache:  store,
+		corpus: hex.EncodeToString(corpus[:6]),
+		inputs: inputs,
+	}
+}
+
+// key returns the key of a stage from its options (and the corpus files)
+func (s *stageCache) key(options ...any) string {
+	if s == nil {
+		return ""
+	}
+
+	key, err := s.cache.Key(append([]any{s.inputs}, options...)...)
+	if err != nil {
+		log.Printf("unable to compute the cache key: %v", err)
+		return ""
+	}
+	return key
+}
+
+func (s *stageCache) loadParse(key string) (*cache.ParseSnapshot, bool) {
+	if s == nil || key == "" {
+		return nil, false
+	}
+	return s.cache.LoadParse(s.corpus, key)
+}
+
+func (s *stageCache) loadAnalysis(key string) (*cache.AnalysisSnapshot, bool) {
+	if s == nil || key == "" {
+		return nil, false
+	}
+	return s.cache.LoadAnalysis(s.corpus, key)
+}
+
+// save stores the snapshot of a stage
+// NOTE: a failure only costs the next run the computation, hence the warning
+func (s *stageCache) save(stage cache.Stage, key string, snapshot any) {
+	if s == nil || key == "" {
+		return
+	}
+
+	if err := s.cache.Save(stage, s.corpus, key, snapshot); err != nil {
+		log.Printf("unable to save the %s stage in the cache: %v", stage, err)
+	}
+}
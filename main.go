@@ -1,22 +1,86 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/glthr/DeMystify/common"
 	"github.com/glthr/DeMystify/graph"
 	"github.com/glthr/DeMystify/parser"
 	pdf "github.com/glthr/DeMystify/renderer"
 	"github.com/glthr/DeMystify/renderer/dot"
+	"github.com/glthr/DeMystify/renderer/report"
 )
 
-const graphFilePath = "generated/graph.dot"
+const (
+	graphFilePath        = "generated/graph.dot"
+	integrityFilePath    = "generated/integrity.json"
+	cacheDirPath         = "generated/cache"
+	errorsFilePath       = "generated/errors.json"
+	condensationPath     = "generated/condensation.dot"
+	dominatorsPath       = "generated/dominators.dot"
+	positionsPath        = "generated/positions.dot"
+	diffTextPath         = "generated/diff.txt"
+	diffDOTPath          = "generated/diff.dot"
+	subgraphPath         = "generated/subgraph.dot"
+	agesPath             = "generated/ages.dot"
+	ageMatrixPath        = "generated/ages.csv"
+	cutContentPath       = "generated/cut_content.dot"
+	cutContentReportPath = "generated/cut_content.json"
+	queryPath            = "generated/query.dot"
+)
+
+// commands (the graph generation is the default command)
+const (
+	dominatorsCommand = "dominators"
+	diffCommand       = "diff"
+	subgraphCommand   = "subgraph"
+	queryCommand      = "query"
+)
+
+// number of nodes listed in each centrality ranking
+const centralityRankingSize = 10
+
+// exit codes
+const (
+	exitFailure        = 1
+	exitPartialSuccess = 2 // the graph was generated, but some files or scripts were skipped
+)
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case dominatorsCommand:
+			runDominators(os.Args[2:])
+			return
+		case diffCommand:
+			runDiff(os.Args[2:])
+			return
+		case subgraphCommand:
+			runSubgraph(os.Args[2:])
+			return
+		case queryCommand:
+			runQuery(os.Args[2:])
+			return
+		}
+	}
+
+	runGenerate(os.Args[1:])
+}
+
+// runGenerate generates the Myst Graph, its analysis, and its renderings (default command)
+func runGenerate(args []string) {
 	// ensure that Neato is installed (to generate the PDF file)
 	// NOTE: should be refactored by using a library to create the PDF file,
 	// or render the graph in a different format
@@ -25,63 +89,129 @@ func main() {
 		log.Fatalf("Neato is not installed: %v", err)
 	}
 
-	if len(os.Args) < 2 {
-		log.Fatal("Usage: go run main.go <xml_hypercard_files_directory_path>")
-	}
+	flags := flag.NewFlagSet("demystify", flag.ExitOnError)
+	graphOptions := registerGraphFlags(flags)
+	scaleBy := flags.String("scale-by", "", "scale the rendered nodes by a centrality metric: indegree, outdegree, betweenness, pagerank, closeness, or eigenvector")
+	colorByCommunity := flags.Bool("color-by-community", false, "color the rendered cards by community instead of by stack")
+	maxCycleLength := flags.Int("max-cycle-length", graph.DefaultCycleOptions().MaxLength, "maximum number of cards of the enumerated cycles")
+	cycleLimit := flags.Int("cycle-limit", graph.DefaultCycleOptions().Limit, "maximum number of enumerated cycles (no limit if <= 0)")
+	cyclesPath := flags.String("cycles", "", "export the cycle catalog to a `.json` or `.csv` file")
+	deadLinksPath := flags.String("dead-links", "", "export the links to non-existent cards to a `.json` or `.csv` file")
+	metricsPath := flags.String("metrics", "", "export the metrics of the graph and of each stack to a `.md`, `.html`, or `.json` file")
+	cycleOverlay := flags.String("cycle-overlay", "", "highlight the cycles of the given kinds on the graph: `all` or a comma-separated list of rotation, corridor, and exploration")
+	positions := flags.Bool("positions", false, "also render the position-level graph (cards grouped by physical position) in "+positionsPath)
+	ages := flags.Bool("ages", false, "also render the Age-level quotient graph in "+agesPath)
+	cutContent := flags.Bool("cut-content", false, "also analyze the graph with the disabled links enabled and the virtual cards made real, and compare (see "+cutContentReportPath+")")
+	renderCutContent := flags.Bool("render-cut-content", false, "with -cut-content, also render the cut-content graph in "+cutContentPath)
+	condensation := flags.Bool("condensation", false, "also render the condensation DAG of the strongly connected components in "+condensationPath)
+	_ = flags.Parse(args)
 
-	stacksDir := os.Args[1]
+	if flags.NArg() < 1 {
+		log.Fatal("Usage: go run . [-profile <name|profile.json>] [-rules <rules.json>] [-scale-by <metric>] [-color-by-community] [-max-cycle-length <n>] [-cycle-limit <n>] [-cycles <catalog.json|csv>] [-dead-links <links.json|csv>] [-metrics <report.md|html|json>] [-cycle-overlay <kinds>] [-positions] [-ages] [-cut-content [-render-cut-content]] [-condensation] [-continue-on-error] [-cost-model <preset>] [-no-cache] <xml_hypercard_files_directory_path>")
+	}
 
-	// parser: extract information from the stacks and cards
-	fmt.Println("Parsing stacks and cards...")
+	stacksDir := flags.Arg(0)
 
-	p, err := parser.NewParser(stacksDir)
-	if err != nil {
-		log.Fatalf("unable to parse stacks and cards: %v", err)
+	var scaleByMetric common.CentralityMetric
+	if *scaleBy != "" {
+		metric, ok := common.ParseCentralityMetric(*scaleBy)
+		if !ok {
+			log.Fatalf("unknown centrality metric: %s", *scaleBy)
+		}
+		scaleByMetric = metric
 	}
 
-	metadata, err := p.Process()
-	if err != nil {
-		log.Fatalf("parser error: %v", err)
+	var overlayKinds []common.CycleKind
+	if *cycleOverlay != "" && *cycleOverlay != "all" {
+		for _, name := range strings.Split(*cycleOverlay, ",") {
+			kind := common.CycleKind(strings.TrimSpace(name))
+			if kind != common.RotationLoop && kind != common.CorridorLoop && kind != common.ExplorationLoop {
+				log.Fatalf("unknown cycle kind: %s", name)
+			}
+			overlayKinds = append(overlayKinds, kind)
+		}
 	}
 
-	// guard clause (helpful for external contributors...)
-	// NOTE: to replace with a more robust mechanism, like hashing the files
-	if metadata.TotalStacks != 6 {
-		log.Fatalf("expected 6 stacks (Ages), got %d", metadata.TotalStacks)
+	cycleOptions := graph.DefaultCycleOptions()
+	cycleOptions.MaxLength = *maxCycleLength
+	cycleOptions.Limit = *cycleLimit
+
+	loaded := loadGraph(stacksDir, graphOptions, cycleOptions)
+	profile, g, metadata := loaded.profile, loaded.graph, loaded.metadata
+
+	// the shortest paths between the start (Myst:8336) and the end (Dunny Age:11088) of the game,
+	// respecting the push card / pop card discipline, are in `metadata.Stats.GoalPaths` (see the profile)
+	for _, goalPath := range metadata.Stats.GoalPaths {
+		fmt.Printf("Shortest path from %s to %s (push/pop): %.4g\n",
+			g.GetNameForID(goalPath.From), g.GetNameForID(goalPath.To), goalPath.Distance)
 	}
 
-	if metadata.TotalCards != 1355 {
-		log.Fatalf("expected 1355 cards, got %d", metadata.TotalCards)
+	PrintPushdown(g, metadata.Stats.Pushdown)
+	PrintReachability(g, metadata.Stats.Reachability)
+	PrintMetrics(metadata.Stats.Metrics.Graph)
+	PrintCentralityRankings(metadata.Stats.Centrality, centralityRankingSize)
+
+	traps := g.GetTrapComponents()
+	fmt.Printf("Strongly connected components: %d (%d trap(s))\n",
+		len(metadata.Stats.StronglyConnectedComponents), len(traps))
+	for _, trap := range traps {
+		fmt.Printf("  SCC %d: %d node(s) in %v\n", trap.ID, len(trap.Nodes), trap.Stacks)
 	}
 
-	fmt.Printf("Stack count: %d\n", metadata.TotalStacks)
-	fmt.Printf("Cards count: %d\n", metadata.TotalCards)
+	PrintStackRobustness(g, metadata.Stats)
+	PrintCommunities(metadata.Stats.Communities)
 
-	// graph generation
-	fmt.Println("Generating the Myst Graph...")
+	cycleCounts := graph.CountCyclesByKind(metadata.Stats.Cycles)
+	fmt.Printf("Cycles (up to %d cards): %d rotation, %d corridor, %d exploration\n", cycleOptions.MaxLength,
+		cycleCounts[common.RotationLoop], cycleCounts[common.CorridorLoop], cycleCounts[common.ExplorationLoop])
+	if metadata.Stats.CyclesTruncated {
+		log.Printf("the enumeration of the cycles stopped at %d cycles (see -cycle-limit)", cycleOptions.Limit)
+	}
 
-	g, err := graph.NewGraph(metadata)
-	if err != nil {
-		log.Fatalf("error while instantiating the graph: %v", err)
+	PrintPositions(metadata.Stats.Positions)
+	PrintAgeLinks(metadata.Stats.AgeQuotient)
+
+	if err := WriteAgeMatrix(g, ageMatrixPath); err != nil {
+		log.Printf("unable to save the cross-age transition matrix: %v", err)
 	}
 
-	g.Process()
+	deadLinks := loaded.deadLinks
+	total, disabled := deadLinks.Count()
+	fmt.Printf("Links to non-existent cards: %d (%d disabled)\n", total, disabled)
 
-	fmt.Printf("Nodes count: %d\n", metadata.TotalNodes)
-	fmt.Printf("Edges count: %d\n", metadata.TotalEdges)
+	if *deadLinksPath != "" {
+		if err := WriteDeadLinks(deadLinks, *deadLinksPath); err != nil {
+			log.Printf("unable to save the dead link report: %v", err)
+		}
+	}
 
-	// calculate the shortest path between start (Myst:8336) and end (Dunny Age:11088) of the game
-	// shortestPath, err := ComputeShortestPath(p, g, "Myst", 8336, "Dunny Age", 11088)
-	// if err != nil {
-	// 	log.Fatalf("error while computing the shortest path: %v", err)
-	// }
+	if *metricsPath != "" {
+		if err := WriteMetricsReport(metadata.Stats.Metrics, *metricsPath); err != nil {
+			log.Printf("unable to save the metrics report: %v", err)
+		}
+	}
+
+	if *cyclesPath != "" {
+		if err := WriteCycleCatalog(g, *cyclesPath); err != nil {
+			log.Printf("unable to save the cycle catalog: %v", err)
+		}
+	}
 
 	// graph rendering
 	fmt.Println("Generating the DOT file...")
 
-	dotGenerator := dot.NewGenerator(g, metadata)
+	dotConfig := dot.DefaultConfig()
+	dotConfig.TagStyles = profile.Rules.Styles
+	dotConfig.StackDisplayNames = profile.StackDisplayNames
+	dotConfig.ScaleByCentrality = scaleByMetric
+	dotConfig.ColorByCommunity = *colorByCommunity
+	if *cycleOverlay != "" {
+		dotConfig.Overlays = dot.CycleOverlays(metadata.Stats.Cycles, overlayKinds...)
+	}
+
+	dotGenerator := dot.NewGenerator(g, metadata, dotConfig)
 
-	// NOTE: replace `nil` (no path) with `shortestPath.Path` (shortest path) or with any other path
+	// NOTE: replace `nil` (no path) with `metadata.Stats.GoalPaths[0].Path` (shortest path) or with any other path
 	// like `metadata.Stats.MostSeparatedNodes.Path` (most separated nodes)
 	dotContent, err := dotGenerator.Generate(nil)
 	if err != nil {
@@ -90,12 +220,60 @@ func main() {
 
 	if err := os.WriteFile(graphFilePath, []byte(dotContent), 0644); err != nil {
 		fmt.Printf("Error saving file: %v\n", err)
-		return
+		os.Exit(exitFailure)
 	}
 
 	fmt.Println("Generating the PDF file...")
 
 	pdf.RenderPDF(graphFilePath)
+
+	if *condensation {
+		fmt.Println("Generating the condensation DAG...")
+
+		condensationConfig := dotConfig
+		condensationConfig.RenderCondensation = true
+		RenderAlternateGraph(g, metadata, condensationConfig, condensationPath)
+	}
+
+	if *positions {
+		fmt.Println("Generating the position-level graph...")
+
+		positionsConfig := dotConfig
+		positionsConfig.RenderPositions = true
+		RenderAlternateGraph(g, metadata, positionsConfig, positionsPath)
+	}
+
+	if *ages {
+		fmt.Println("Generating the Age-level quotient graph...")
+
+		agesConfig := dotConfig
+		agesConfig.RenderAgeQuotient = true
+		RenderAlternateGraph(g, metadata, agesConfig, agesPath)
+	}
+
+	if *cutContent {
+		RunCutContent(loaded, cycleOptions, dotConfig, *renderCutContent)
+	}
+
+	if loaded.parseErrors.HasErrors() {
+		os.Exit(exitPartialSuccess)
+	}
+}
+
+// RenderAlternateGraph saves an alternate rendering of the graph (e.g., the condensation DAG)
+// as DOT and PDF files
+func RenderAlternateGraph(g *graph.MystGraph, metadata *common.Metadata, config dot.Config, path string) {
+	content, err := dot.NewGenerator(g, metadata, config).Generate(nil)
+	if err != nil {
+		log.Fatalf("error generating the rendering of %s: %v", path, err)
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		fmt.Printf("Error saving file: %v\n", err)
+		os.Exit(exitFailure)
+	}
+
+	pdf.RenderPDF(path)
 }
 
 func ComputeShortestPath(
@@ -118,7 +296,7 @@ func ComputeShortestPath(
 
 	to, err := p.GetCardByStackAndID(toStack, toID)
 	if err != nil {
-		log.Fatalf("error while getting card: %v", err)
+		return nil, err
 	}
 
 	toNode, ok := g.GetNodeID(to.Name)
@@ -129,6 +307,277 @@ func ComputeShortestPath(
 	return g.ComputeShortestPath(fromNode, toNode, nil)
 }
 
+// WriteIntegrityReport prints a summary of the integrity report and saves it as JSON
+func WriteIntegrityReport(report *parser.IntegrityReport, path string) error {
+	if len(report.Issues) == 0 {
+		fmt.Println("Integrity check: no issue found")
+	} else {
+		fmt.Printf("Integrity check: %d issue(s) found (see %s)\n", len(report.Issues), path)
+		counts := report.CountByKind()
+		kinds := make([]string, 0, len(counts))
+		for kind := range counts {
+			kinds = append(kinds, string(kind))
+		}
+		sort.Strings(kinds)
+
+		for _, kind := range kinds {
+			fmt.Printf("  %s: %d\n", kind, counts[parser.IntegrityIssueKind(kind)])
+		}
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return report.WriteJSON(file)
+}
+
+// PrintReachability prints the cards unreachable from the entry card, the soft-locks,
+// and the cards reachable only through the cut content, with their witness paths or explanations
+func PrintReachability(g *graph.MystGraph, stats common.ReachabilityStats) {
+	// NOTE: without an entry card (e.g., with the generic profile), there is nothing to report
+	if _, err := g.EntryNodeID(); err != nil {
+		return
+	}
+
+	fmt.Printf("Reachability from %s: %d unreachable card(s), %d soft-lock(s), %d card(s) reachable only through disabled links or virtual cards\n",
+		stats.Entry.Name, len(stats.Unreachable), len(stats.SoftLocks), len(stats.CutOnly))
+
+	printItems := func(title string, items []common.ReachabilityInfo) {
+		if len(items) == 0 {
+			return
+		}
+
+		fmt.Printf("  %s:\n", title)
+		for _, item := range items {
+			if item.Path != nil {
+				fmt.Printf("    %s: %s (%v)\n", item.Node.Name, item.Explanation, g.FormatPathAsNames(item.Path))
+			} else {
+				fmt.Printf("    %s: %s\n", item.Node.Name, item.Explanation)
+			}
+		}
+	}
+
+	printItems("unreachable", stats.Unreachable)
+	printItems("soft-locks", stats.SoftLocks)
+	printItems("reachable only through the cut content", stats.CutOnly)
+}
+
+// PrintMetrics prints the main global metrics of the graph
+func PrintMetrics(metrics common.GraphMetrics) {
+	fmt.Printf("Metrics: diameter %.4g, radius %.4g, density %.4f, reciprocity %.4f, average clustering %.4f, assortativity %.4f\n",
+		metrics.Diameter, metrics.Radius, metrics.Density, metrics.Reciprocity, metrics.AverageClustering, metrics.Assortativity)
+}
+
+// PrintPushdown prints the push/pop summaries, and the nodes reachable only by following
+// the links to popping cards
+func PrintPushdown(g *graph.MystGraph, stats common.PushdownStats) {
+	returning := 0
+	for _, summary := range stats.Summaries {
+		if len(summary.Poppers) > 0 {
+			returning++
+		}
+	}
+
+	fmt.Printf("Push/pop: %d pushing card(s), %d returning with pop card\n", len(stats.Summaries), returning)
+	if _, err := g.EntryNodeID(); err != nil {
+		return
+	}
+
+	fmt.Printf("  %d node(s) reachable from the entry card, %d only through pushing cards\n",
+		stats.Reachable, len(stats.ReachableThroughPush))
+	for _, node := range stats.ReachableThroughPush {
+		fmt.Printf("    %s\n", node.Name)
+	}
+}
+
+// PrintCentralityRankings prints the top nodes for each centrality metric,
+// and the degree distributions
+func PrintCentralityRankings(stats common.CentralityStats, n int) {
+	for _, metric := range common.CentralityMetrics {
+		fmt.Printf("Top %d nodes by %s:\n", n, metric)
+		for rank, c := range stats.Ranked(metric, n) {
+			fmt.Printf("  %2d. %-40s %.6g\n", rank+1, c.Name, c.Value(metric))
+		}
+	}
+
+	printDistribution := func(label string, distribution map[int]int) {
+		degrees := make([]int, 0, len(distribution))
+		for degree := range distribution {
+			degrees = append(degrees, degree)
+		}
+		sort.Ints(degrees)
+
+		fmt.Printf("%s degree distribution (degree: nodes):", label)
+		for _, degree := range degrees {
+			fmt.Printf(" %d:%d", degree, distribution[degree])
+		}
+		fmt.Println()
+	}
+
+	printDistribution("In", stats.InDegreeDistribution)
+	printDistribution("Out", stats.OutDegreeDistribution)
+}
+
+// PrintStackRobustness prints, for each stack, its bridges, its articulation cards,
+// and its edge connectivity to the other stacks
+func PrintStackRobustness(g *graph.MystGraph, stats common.GraphStats) {
+	var stacks []string
+	for _, c := range stats.StackConnectivity {
+		if !slices.Contains(stacks, c.From) {
+			stacks = append(stacks, c.From)
+		}
+	}
+
+	for _, stack := range stacks {
+		fmt.Printf("Robustness of %s:\n", stack)
+
+		for _, bridge := range stats.Bridges {
+			if g.StackOf(bridge.From.ID) == stack || g.StackOf(bridge.To.ID) == stack {
+				fmt.Printf("  bridge: %s -- %s (%d edge(s))\n", bridge.From.Name, bridge.To.Name, bridge.Count)
+			}
+		}
+
+		for _, node := range stats.ArticulationPoints {
+			if g.StackOf(node.ID) == stack {
+				fmt.Printf("  articulation card: %s\n", node.Name)
+			}
+		}
+
+		for _, c := range stats.StackConnectivity {
+			if c.From != stack || c.EdgeConnectivity == 0 {
+				continue
+			}
+
+			fmt.Printf("  to %s: %d edge-disjoint path(s)", c.To, c.EdgeConnectivity)
+			if c.EdgeConnectivity == 1 {
+				fmt.Printf(", hanging on %s -> %s", c.MinCut[0].From.Name, c.MinCut[0].To.Name)
+			}
+			fmt.Println()
+		}
+	}
+}
+
+// PrintCommunities prints the comparison between the communities and the stacks:
+// the confusion matrix and the cards whose community disagrees with their stack
+func PrintCommunities(stats common.CommunityStats) {
+	fmt.Printf("Communities: %d (modularity: %.4f, NMI with the stacks: %.4f)\n",
+		len(stats.Communities), stats.Modularity, stats.NMI)
+
+	fmt.Printf("  %-20s", "stack \\ community")
+	for c := range stats.Communities {
+		fmt.Printf(" %5d", c)
+	}
+	fmt.Println()
+
+	for s, stack := range stats.Stacks {
+		fmt.Printf("  %-20s", stack)
+		for _, count := range stats.ConfusionMatrix[s] {
+			fmt.Printf(" %5d", count)
+		}
+		fmt.Println()
+	}
+
+	fmt.Printf("Cards whose community disagrees with their stack: %d\n", len(stats.Disagreements))
+	for _, d := range stats.Disagreements {
+		fmt.Printf("  %s (%s) in community %d (mostly %s)\n", d.Node.Name, d.Stack, d.Community, d.CommunityMainStack)
+	}
+}
+
+// PrintPositions prints the number of physical positions of each stack
+func PrintPositions(stats common.PositionStats) {
+	cards := make(map[string]int)
+	positions := make(map[string]int)
+	var stacks []string
+	for _, position := range stats.Positions {
+		if positions[position.Stack] == 0 {
+			stacks = append(stacks, position.Stack)
+		}
+		positions[position.Stack]++
+		cards[position.Stack] += len(position.Cards)
+	}
+
+	fmt.Printf("Positions: %d (from %d nodes)\n", len(stats.Positions), len(stats.NodePosition))
+	for _, stack := range stacks {
+		fmt.Printf("  %s: %d nodes -> %d positions\n", stack, cards[stack], positions[stack])
+	}
+}
+
+// PrintAgeLinks prints the number of links between the stacks, with their entry cards
+func PrintAgeLinks(quotient common.AgeQuotient) {
+	fmt.Printf("Links between stacks (see %s):\n", ageMatrixPath)
+	for _, link := range quotient.Links {
+		entries := make([]string, 0, len(link.EntryCards))
+		for _, card := range link.EntryCards {
+			entries = append(entries, card.Name)
+		}
+
+		fmt.Printf("  %s -> %s: %d link(s) (%d disabled), entering at %s\n", link.From, link.To,
+			link.Count, link.AttributeCounts[common.Disabled.String()], strings.Join(entries, ", "))
+	}
+}
+
+// WriteAgeMatrix saves the cross-age transition matrix as CSV
+func WriteAgeMatrix(g *graph.MystGraph, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return g.WriteAgeMatrix(file)
+}
+
+// WriteCycleCatalog saves the cycle catalog (the format is given by the file extension)
+func WriteCycleCatalog(g *graph.MystGraph, path string) error {
+	return writeFormattedFile(path, g.WriteCycleCatalog)
+}
+
+// WriteMetricsReport saves the metrics report in the format given by the file extension
+func WriteMetricsReport(metrics common.MetricsReport, path string) error {
+	return writeFormattedFile(path, func(w io.Writer, format string) error {
+		return report.WriteMetrics(w, metrics, format)
+	})
+}
+
+// writeFormattedFile saves the output of a writer taking the format given by the file extension
+// NOTE: the output is buffered, so that an unknown format (or any other error) leaves no file
+func writeFormattedFile(path string, write func(w io.Writer, format string) error) error {
+	var buffer bytes.Buffer
+	if err := write(&buffer, strings.TrimPrefix(filepath.Ext(path), ".")); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buffer.Bytes(), 0644)
+}
+
+// WriteDeadLinks saves the dead link report in the format given by the file extension
+func WriteDeadLinks(report *parser.DeadLinkReport, path string) error {
+	return writeFormattedFile(path, report.Write)
+}
+
+// WriteErrorReport prints the errors skipped in the "continue on error" mode and saves them as JSON
+func WriteErrorReport(report *common.ErrorReport, path string) error {
+	entries := report.Entries()
+
+	fmt.Printf("%d error(s) skipped (see %s)\n", len(entries), path)
+	for _, entry := range entries {
+		fmt.Printf("  %s\n", entry.Message)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(entries)
+}
+
 func CheckNeatoInstalled() error {
 	var cmd *exec.Cmd
 	if runtime.GOOS == "windows" {
@@ -1,7 +1,6 @@
 package parser
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/glthr/DeMystify/common"
@@ -25,22 +24,27 @@ func (p *Parser) Process() (*common.Metadata, error) {
 	for _, card := range p.cards {
 		node := &common.Node{
 			Attributes:    []common.NodeAttribute{common.IsCard},
+			CardID:        card.ID,
 			StackName:     card.Stack.Name,
 			Name:          card.Name,
 			OriginalName:  card.OriginalName,
 			SecondaryName: card.Background,
 		}
 
-		if card.HasBluePage {
-			node.Attributes = append(node.Attributes, common.ContainsBluePage)
+		if card.IsPushCard {
+			node.Attributes = append(node.Attributes, common.PushesCard)
 		}
-
-		if card.HasRedPage {
-			node.Attributes = append(node.Attributes, common.ContainsRedPage)
+		if card.IsPopCard {
+			node.Attributes = append(node.Attributes, common.PopsCard)
 		}
 
-		if card.HasWhitePage {
-			node.Attributes = append(node.Attributes, common.ContainsWhitePage)
+		// tags named after a node attribute (e.g., the pages) set that attribute
+		for _, tag := range card.Tags {
+			if attr, ok := common.ParseNodeAttribute(tag); ok {
+				node.Attributes = append(node.Attributes, attr)
+			} else {
+				node.Tags = append(node.Tags, tag)
+			}
 		}
 
 		metadata.Nodes = append(metadata.Nodes, node)
@@ -57,18 +61,25 @@ func (p *Parser) Process() (*common.Metadata, error) {
 			if cardTarget, ok := link.Target.(*HyperCardCard); ok {
 				targetNode = &common.Node{
 					Attributes: []common.NodeAttribute{common.IsCard, common.IsVirtual},
+					CardID:     cardTarget.ID,
 					StackName:  cardTarget.Stack.Name,
 					Name:       cardTarget.Name,
 				}
 
 				metadata.Nodes = append(metadata.Nodes, targetNode)
 			} else {
-				return nil, errors.New("target node not found")
+				if err := p.handleError(fmt.Errorf("target node not found: %w", common.NodeNotFoundErr)); err != nil {
+					return nil, err
+				}
+				continue // skip the link
 			}
 		}
 
 		if sourceNode == nil {
-			return nil, errors.New("source node not found")
+			if err := p.handleError(fmt.Errorf("source node not found: %w", common.NodeNotFoundErr)); err != nil {
+				return nil, err
+			}
+			continue // skip the link
 		}
 
 		edge := &common.Edge{
@@ -76,9 +87,10 @@ func (p *Parser) Process() (*common.Metadata, error) {
 			Source:         sourceNode,
 			Target:         targetNode,
 			TransitivityID: link.TransitivityID,
+			Tags:           link.Tags,
 		}
 
-		key := fmt.Sprintf("%s-%s:%v:%d", edge.Source.Name, edge.Target.Name, edge.Attributes, edge.TransitivityID)
+		key := fmt.Sprintf("%s-%s:%v:%d:%v", edge.Source.Name, edge.Target.Name, edge.Attributes, edge.TransitivityID, edge.Tags)
 		if !edgeExists[key] {
 			// only record an edge once, as an identical link can appear multiple time in a script
 			metadata.Edges = append(metadata.Edges, edge)
@@ -140,5 +152,13 @@ func getEdgeAttributes(
 		edgeTypes = append(edgeTypes, common.RestrictiveTransitivityHead)
 	}
 
+	if link.IsAutomatic {
+		edgeTypes = append(edgeTypes, common.Automatic)
+	}
+
+	if link.HasTransition {
+		edgeTypes = append(edgeTypes, common.TransitionEffect)
+	}
+
 	return edgeTypes
 }
@@ -2,10 +2,13 @@ package parser
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"strings"
+	"slices"
+
+	"github.com/glthr/DeMystify/common"
 )
 
 type HyperCardCard struct {
@@ -18,9 +21,16 @@ type HyperCardCard struct {
 	Scripts      []HyperTalk
 	IsPushCard   bool
 	IsPopCard    bool
-	HasBluePage  bool
-	HasRedPage   bool
-	HasWhitePage bool
+	Tags         []string // produced by the node rules
+}
+
+// addTags adds the tags that the card does not already have
+func (c *HyperCardCard) addTags(tags ...string) {
+	for _, tag := range tags {
+		if !slices.Contains(c.Tags, tag) {
+			c.Tags = append(c.Tags, tag)
+		}
+	}
 }
 
 // SimplePart contains only the script from a part
@@ -39,13 +49,13 @@ type simpleContent struct {
 func ParseSimpleCard(parentStack *HyperCardStack, filepath string, cardIdNameMap map[int]string) (*HyperCardCard, error) {
 	file, err := os.Open(filepath)
 	if err != nil {
-		return nil, err
+		return nil, &common.ParseError{File: filepath, Err: err}
 	}
 	defer file.Close()
 
 	content, err := io.ReadAll(file)
 	if err != nil {
-		return nil, err
+		return nil, &common.ParseError{File: filepath, Err: err}
 	}
 
 	type simpleCard struct {
@@ -61,22 +71,13 @@ func ParseSimpleCard(parentStack *HyperCardStack, filepath string, cardIdNameMap
 
 	err = xml.Unmarshal(content, &c)
 	if err != nil {
-		return nil, err
+		return nil, newXMLError(filepath, err)
 	}
 
 	// process script for each part
 	var scripts []HyperTalk
 	processRawScript := func(rawScript string) {
-		scriptLines := HyperTalk{}
-		for _, line := range splitScript(rawScript) {
-			line = strings.TrimSpace(line)
-			scriptLines.lines = append(scriptLines.lines, ScriptLine{
-				Line:       line,
-				IsDisabled: strings.HasPrefix(line, "--"),
-			})
-		}
-
-		scripts = append(scripts, scriptLines)
+		scripts = append(scripts, parseScript(rawScript))
 	}
 
 	for _, part := range c.Parts {
@@ -109,3 +110,15 @@ func ParseSimpleCard(parentStack *HyperCardStack, filepath string, cardIdNameMap
 		Scripts:      scripts,
 	}, nil
 }
+
+// newXMLError locates an XML decoding error in its file
+func newXMLError(filepath string, err error) error {
+	parseErr := &common.ParseError{File: filepath, Err: err}
+
+	var syntaxErr *xml.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		parseErr.Line = syntaxErr.Line
+	}
+
+	return parseErr
+}
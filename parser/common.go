@@ -2,12 +2,15 @@ package parser
 
 import (
 	"fmt"
+	"hash/fnv"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strings"
 
 	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
 )
 
 // NOTE: perhaps refactor, depending on how the original files are decompiled
@@ -15,9 +18,21 @@ const stackFileName = "stack_-1.xml"
 
 type Parser struct {
 	stacksDir string
+	options   Options
 	stacks    []*HyperCardStack
 	cards     []*HyperCardCard
 	links     []*HyperCardLink
+	deadLinks []DeadLink // links to virtual cards
+	report    common.ErrorReport
+}
+
+// Options configures the parser
+type Options struct {
+	// Rules tag the cards and links (the default rules are used if nil)
+	Rules *config.Rules
+	// ContinueOnError skips the malformed files and scripts instead of aborting,
+	// and collects the errors in a report (see Parser.Errors)
+	ContinueOnError bool
 }
 
 type HyperCardLink struct {
@@ -26,15 +41,33 @@ type HyperCardLink struct {
 	IsNotImplemented bool // points to from another card, but does not exist
 	IsDisabled       bool // commented out script line
 	IsBacktracking   bool
+	IsAutomatic      bool     // followed without any player action (see automaticHandlers)
+	HasTransition    bool     // preceded by a visual effect
+	Tags             []string // produced by the edge rules
 
 	// Transitivity
 	TransitivityRank common.TransitivityRank
 	TransitivityID   int64
 }
 
-func NewParser(stacksDir string) (*Parser, error) {
+// transitivityID identifies the two halves of a link split at a transitive card, from the
+// stack and ID of the source card and the position of the link among its links, so that it
+// does not depend on the parsing order
+func transitivityID(source *HyperCardCard, position int) int64 {
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%s:%d:%d", source.Stack.Name, source.ID, position)
+	return int64(h.Sum64() & math.MaxInt64)
+}
+
+// NewParser parses the stacks and cards found in stacksDir
+func NewParser(stacksDir string, options Options) (*Parser, error) {
+	if options.Rules == nil {
+		options.Rules = config.DefaultRules()
+	}
+
 	p := &Parser{
 		stacksDir: stacksDir,
+		options:   options,
 	}
 
 	stacksPaths, err := p.getPaths()
@@ -109,19 +142,55 @@ func findCardFiles(dir string) ([]string, error) {
 	return cardPaths, nil
 }
 
+// Errors returns the errors collected in the "continue on error" mode
+func (p *Parser) Errors() *common.ErrorReport {
+	return &p.report
+}
+
+// handleError records the error and returns nil in the "continue on error" mode,
+// or returns the error otherwise
+func (p *Parser) handleError(err error) error {
+	if err == nil || !p.options.ContinueOnError {
+		return err
+	}
+
+	p.report.Add(err)
+	return nil
+}
+
 func (p *Parser) parseStacksAndCards(stacksPaths []Paths) error {
+	cardFiles := make(map[string]string) // card name -> file
+
 	for _, stackPath := range stacksPaths {
 		stack, cardIdNameMap, err := ParseStackFile(stackPath.stackFilepath)
 		if err != nil {
-			return err
+			if err = p.handleError(err); err != nil {
+				return err
+			}
+			continue // skip the stack
 		}
 		p.stacks = append(p.stacks, stack)
 
 		for _, cardPath := range stackPath.cardsFilepaths {
 			card, parseErr := ParseSimpleCard(stack, cardPath, cardIdNameMap)
 			if parseErr != nil {
-				return parseErr
+				if parseErr = p.handleError(parseErr); parseErr != nil {
+					return parseErr
+				}
+				continue // skip the card
+			}
+
+			if file, exists := cardFiles[card.Name]; exists {
+				dupErr := &common.ParseError{
+					File: cardPath,
+					Err:  fmt.Errorf("card %s is already defined in %s: %w", card.Name, file, common.NodeAlreadyExistsErr),
+				}
+				if dupErr := p.handleError(dupErr); dupErr != nil {
+					return dupErr
+				}
+				continue // skip the duplicate card
 			}
+			cardFiles[card.Name] = cardPath
 
 			p.cards = append(p.cards, card)
 		}
@@ -134,15 +203,24 @@ func (p *Parser) identifyLinks() error {
 		for _, script := range stack.Script {
 			for _, line := range script.lines {
 				if link, err := p.parseCardCommand(stack, line.Line); err != nil {
-					return fmt.Errorf("cannot get HyperCardLink: %w", err)
+					if err = p.handleError(newScriptError(stack.Filepath(), line, err)); err != nil {
+						return err
+					}
 				} else if link != nil {
+					link.IsAutomatic = line.isAutomatic()
+					link.HasTransition = line.HasVisualEffect
+					if link.IsNotImplemented {
+						p.addDeadLink(stack, stack.Filepath(), line, link)
+					}
 					p.links = append(p.links, link)
 				}
 			}
 		}
 	}
 
-	for i, card := range p.cards {
+	for _, card := range p.cards {
+		// position of the links among all the links of the card (see transitivityID)
+		offset := 0
 		for _, script := range card.Scripts {
 			var (
 				goToCards []*HyperCardCard
@@ -150,12 +228,19 @@ func (p *Parser) identifyLinks() error {
 			)
 			for _, line := range script.lines {
 				if link, err := p.parseCardCommand(card, line.Line); err != nil {
-					return fmt.Errorf("cannot get HyperCardLink: %w", err)
+					if err = p.handleError(newScriptError(card.Filepath, line, err)); err != nil {
+						return err
+					}
 				} else if link != nil {
 					if link.TransitivityRank == common.DefaultTransitivity {
 						goToCards = append(goToCards, link.Target.(*HyperCardCard))
 					}
 					link.IsDisabled = line.IsDisabled
+					link.IsAutomatic = line.isAutomatic()
+					link.HasTransition = line.HasVisualEffect
+					if link.IsNotImplemented {
+						p.addDeadLink(card, card.Filepath, line, link)
+					}
 					links = append(links, link)
 				}
 			}
@@ -171,11 +256,14 @@ func (p *Parser) identifyLinks() error {
 						Source:           link.Source,
 						Target:           goToCards[j],
 						TransitivityRank: common.Tail,
-						TransitivityID:   int64(i),
+						TransitivityID:   transitivityID(card, offset+j),
 						IsCrossAges:      link.IsCrossAges,
 						IsNotImplemented: link.IsNotImplemented,
 						IsDisabled:       link.IsDisabled,
 						IsBacktracking:   false,
+						IsAutomatic:      link.IsAutomatic,
+						HasTransition:    link.HasTransition,
+						Tags:             link.Tags,
 					})
 
 					// transitive card to target
@@ -183,11 +271,14 @@ func (p *Parser) identifyLinks() error {
 						Source:           goToCards[j],
 						Target:           link.Target,
 						TransitivityRank: common.Head,
-						TransitivityID:   int64(i),
+						TransitivityID:   transitivityID(card, offset+j),
 						IsCrossAges:      link.IsCrossAges,
 						IsNotImplemented: link.IsNotImplemented,
 						IsDisabled:       link.IsDisabled,
 						IsBacktracking:   false,
+						IsAutomatic:      link.IsAutomatic,
+						HasTransition:    link.HasTransition,
+						Tags:             link.Tags,
 					})
 				} else {
 					filteredLinks = append(filteredLinks, link)
@@ -195,6 +286,7 @@ func (p *Parser) identifyLinks() error {
 			}
 
 			p.links = append(p.links, filteredLinks...)
+			offset += len(links)
 		}
 	}
 
@@ -255,6 +347,16 @@ func (p *Parser) identifyLinks() error {
 	return nil
 }
 
+// newScriptError locates an error raised while parsing a script line
+func newScriptError(file string, line ScriptLine, err error) error {
+	return &common.ParseError{
+		File:    file,
+		Line:    line.Number,
+		Context: line.Line,
+		Err:     fmt.Errorf("cannot get HyperCardLink: %w", err),
+	}
+}
+
 func splitScript(script string) []string {
 	if script == "" {
 		return []string{}
@@ -308,5 +410,5 @@ func (p *Parser) GetStackByName(stackName string) (*HyperCardStack, error) {
 			return stack, nil
 		}
 	}
-	return nil, fmt.Errorf("stack with name %s not found", stackName)
+	return nil, &common.UnknownStackError{Stack: stackName}
 }
@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DeadLink is a link to a card that does not exist (virtual card)
+type DeadLink struct {
+	Stack      string `json:"stack"`                // requested stack
+	Card       string `json:"card"`                 // requested card ID or name
+	Source     string `json:"source"`               // card (or stack) whose script contains the link
+	File       string `json:"file"`                 // file of the script
+	Line       int    `json:"line"`                 // 1-based line number in the script
+	Script     string `json:"script"`               // script line
+	IsDisabled bool   `json:"disabled"`             // commented out script line
+	Suggestion string `json:"suggestion,omitempty"` // closest existing card of the requested stack
+}
+
+// DeadLinkGroup contains the dead links to the cards of a stack
+type DeadLinkGroup struct {
+	Stack string     `json:"stack"`
+	Links []DeadLink `json:"links"`
+}
+
+// DeadLinkReport lists the dead links of the corpus, grouped by requested stack
+type DeadLinkReport struct {
+	Stacks []DeadLinkGroup `json:"stacks"`
+}
+
+// addDeadLink records a link to a virtual card, with its script context
+func (p *Parser) addDeadLink(source any, file string, line ScriptLine, link *HyperCardLink) {
+	target, ok := link.Target.(*HyperCardCard)
+	if !ok {
+		return
+	}
+
+	var sourceName string
+	switch s := source.(type) {
+	case *HyperCardCard:
+		sourceName = s.Name
+	case *HyperCardStack:
+		sourceName = s.Name
+	}
+
+	p.deadLinks = append(p.deadLinks, DeadLink{
+		Stack:      target.Stack.Name,
+		Card:       strings.TrimPrefix(target.Name, target.Stack.Name+":"),
+		Source:     sourceName,
+		File:       file,
+		Line:       line.Number,
+		Script:     strings.TrimSpace(line.Line),
+		IsDisabled: line.IsDisabled,
+	})
+}
+
+// DeadLinks returns the links to the cards that do not exist, with the closest existing card
+// of the requested stack: closest card ID, or closest card name (edit distance)
+func (p *Parser) DeadLinks() *DeadLinkReport {
+	groups := make(map[string][]DeadLink)
+	for _, link := range p.deadLinks {
+		link.Suggestion = p.suggestCard(link.Stack, link.Card)
+		groups[link.Stack] = append(groups[link.Stack], link)
+	}
+
+	report := &DeadLinkReport{Stacks: []DeadLinkGroup{}}
+	for stack, links := range groups {
+		sort.SliceStable(links, func(i, j int) bool {
+			a, b := links[i], links[j]
+			if a.Card != b.Card {
+				return a.Card < b.Card
+			}
+			if a.Source != b.Source {
+				return a.Source < b.Source
+			}
+			return a.Line < b.Line
+		})
+		report.Stacks = append(report.Stacks, DeadLinkGroup{Stack: stack, Links: links})
+	}
+
+	sort.Slice(report.Stacks, func(i, j int) bool {
+		return report.Stacks[i].Stack < report.Stacks[j].Stack
+	})
+
+	return report
+}
+
+// suggestCard returns the name of the existing card of the stack closest to the requested one
+// NOTE: a card requested by name has no close match if more than half of its name differs
+func (p *Parser) suggestCard(stackName, requested string) string {
+	var (
+		suggestion string
+		best       = -1
+	)
+
+	requestedID, err := strconv.Atoi(requested)
+	isID := err == nil
+
+	for _, card := range p.cards {
+		if !strings.EqualFold(card.Stack.Name, stackName) {
+			continue
+		}
+
+		var distance int
+		if isID {
+			distance = max(card.ID-requestedID, requestedID-card.ID)
+		} else {
+			if card.OriginalName == nil {
+				continue
+			}
+			distance = editDistance(strings.ToLower(requested), strings.ToLower(*card.OriginalName))
+			if distance > len(requested)/2 {
+				continue
+			}
+		}
+
+		if best == -1 || distance < best || (distance == best && card.Name < suggestion) {
+			best = distance
+			suggestion = card.Name
+		}
+	}
+
+	return suggestion
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+
+	previous := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current := make([]int, len(rb)+1)
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			substitution := previous[j-1]
+			if ra[i-1] != rb[j-1] {
+				substitution++
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, substitution)
+		}
+		previous = current
+	}
+
+	return previous[len(rb)]
+}
+
+// Count returns the number of dead links, and the number of disabled ones
+func (r *DeadLinkReport) Count() (total, disabled int) {
+	for _, group := range r.Stacks {
+		for _, link := range group.Links {
+			total++
+			if link.IsDisabled {
+				disabled++
+			}
+		}
+	}
+	return total, disabled
+}
+
+// Write writes the report in the given format: `json` (grouped by stack) or `csv`
+func (r *DeadLinkReport) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(r)
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"stack", "card", "source", "file", "line", "script", "disabled", "suggestion"}); err != nil {
+			return err
+		}
+		for _, group := range r.Stacks {
+			for _, link := range group.Links {
+				if err := writer.Write([]string{
+					link.Stack,
+					link.Card,
+					link.Source,
+					link.File,
+					strconv.Itoa(link.Line),
+					link.Script,
+					strconv.FormatBool(link.IsDisabled),
+					link.Suggestion,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		return fmt.Errorf("unknown dead link report format %q (expected json or csv)", format)
+	}
+}
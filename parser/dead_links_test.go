@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testCard is a card of a test corpus: its name and the script of its single button
+type testCard struct {
+	id     int
+	name   string
+	script string
+}
+
+// writeStack writes the stack file and the card files of a stack in the corpus
+func writeStack(t *testing.T, corpus, stack string, cards ...testCard) {
+	t.Helper()
+
+	dir := filepath.Join(corpus, stack)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, `<?xml version="1.0"?>`+"\n"+`<stack><name>%s</name><id>-1</id><script></script><background id="100" file="background_100.xml" name=""/>`, stack)
+	for _, card := range cards {
+		fmt.Fprintf(&index, "\n"+`<card id="%d" file="card_%d.xml" marked="false" name="%s" owner="100"/>`, card.id, card.id, card.name)
+
+		content := fmt.Sprintf(`<?xml version="1.0"?>`+"\n"+`<card><id>%d</id><part><script>on mouseUp`+"\n%s\n"+`end mouseUp</script></part><script></script></card>`,
+			card.id, html.EscapeString(card.script))
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("card_%d.xml", card.id)), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	index.WriteString("</stack>")
+
+	if err := os.WriteFile(filepath.Join(dir, stackFileName), []byte(index.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeadLinks(t *testing.T) {
+	corpus := t.TempDir()
+	writeStack(t, corpus, "Myst",
+		testCard{8336, "start", "go card id 8337\ngo card \"docks\" of stack \"Myst\"\n-- go card id 8400 of stack \"Myst\""},
+		testCard{8337, "", "go card id 8338\ngo card \"zzzzzz\" of stack \"Myst\""},
+		testCard{8340, "dock", "go card id 1005 of stack \"Dunny Age\""},
+	)
+	writeStack(t, corpus, "Dunny Age",
+		testCard{1000, "", "go card id 8340 of stack \"Myst\""},
+	)
+
+	p, err := NewParser(corpus, Options{})
+	if err != nil {
+		t.Fatalf("unable to parse the corpus: %v", err)
+	}
+
+	report := p.DeadLinks()
+
+	var links []string
+	for _, group := range report.Stacks {
+		for _, link := range group.Links {
+			if link.Stack != group.Stack {
+				t.Errorf("the link to %s:%s is grouped under %s", link.Stack, link.Card, group.Stack)
+			}
+			links = append(links, fmt.Sprintf("%s:%s from %s (%s:%d, disabled: %t) -> %q",
+				link.Stack, link.Card, link.Source, filepath.Base(link.File), link.Line, link.IsDisabled, link.Suggestion))
+		}
+	}
+
+	// closest card ID, or closest card name (none if too different)
+	expected := []string{
+		`Dunny Age:1005 from Myst:8340 (card_8340.xml:2, disabled: false) -> "Dunny Age:1000"`,
+		`Myst:8338 from Myst:8337 (card_8337.xml:2, disabled: false) -> "Myst:8337"`,
+		`Myst:8400 from Myst:8336 (card_8336.xml:4, disabled: true) -> "Myst:8340"`,
+		`Myst:docks from Myst:8336 (card_8336.xml:3, disabled: false) -> "Myst:8340"`,
+		`Myst:zzzzzz from Myst:8337 (card_8337.xml:3, disabled: false) -> ""`,
+	}
+	if strings.Join(links, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("got the dead links:\n%s\nexpected:\n%s", strings.Join(links, "\n"), strings.Join(expected, "\n"))
+	}
+	if script := report.Stacks[1].Links[2].Script; script != `go card "docks" of stack "Myst"` {
+		t.Errorf("got the script line %q", script)
+	}
+
+	if total, disabled := report.Count(); total != 5 || disabled != 1 {
+		t.Errorf("got %d dead links (%d disabled), expected 5 (1 disabled)", total, disabled)
+	}
+
+	var csv bytes.Buffer
+	if err := report.Write(&csv, "CSV"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(csv.String()), "\n"); len(lines) != 6 || lines[0] != "stack,card,source,file,line,script,disabled,suggestion" {
+		t.Errorf("got the CSV report:\n%s", csv.String())
+	}
+	if err := report.Write(&csv, "yaml"); err == nil {
+		t.Errorf("expected an error for an unknown format")
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"dock", "dock", 0},
+		{"docks", "dock", 1},
+		{"", "dock", 4},
+		{"kitten", "sitting", 3},
+		{"clock tower", "clocktower", 1},
+		{"säule", "saule", 1},
+	}
+
+	for _, test := range tests {
+		if distance := editDistance(test.a, test.b); distance != test.distance {
+			t.Errorf("%q, %q: got %d, expected %d", test.a, test.b, distance, test.distance)
+		}
+		if distance := editDistance(test.b, test.a); distance != test.distance {
+			t.Errorf("%q, %q: got %d, expected %d", test.b, test.a, distance, test.distance)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+type IntegrityIssueKind string
+
+const (
+	OrphanCardFile    IntegrityIssueKind = "orphan_card_file"   // card file not listed in the stack
+	MissingCardFile   IntegrityIssueKind = "missing_card_file"  // listed card without a file
+	DuplicateCardID   IntegrityIssueKind = "duplicate_card_id"  // card ID listed several times in the stack
+	CardIDMismatch    IntegrityIssueKind = "card_id_mismatch"   // card file ID differs from the card `<id>`
+	MissingBackground IntegrityIssueKind = "missing_background" // background referenced by a card owner does not exist
+)
+
+// IntegrityIssue is an inconsistency between a stack card list and its card files
+type IntegrityIssue struct {
+	Kind   IntegrityIssueKind `json:"kind"`
+	Stack  string             `json:"stack"`
+	CardID int                `json:"cardId,omitempty"`
+	File   string             `json:"file,omitempty"`
+	Detail string             `json:"detail"`
+}
+
+// IntegrityReport lists the inconsistencies found in the corpus
+type IntegrityReport struct {
+	Stacks int              `json:"stacks"`
+	Cards  int              `json:"cards"`
+	Issues []IntegrityIssue `json:"issues"`
+}
+
+var cardFileIDPattern = regexp.MustCompile(`^card_(\d+)\.xml$`)
+
+// CheckIntegrity verifies that the cards listed in each stack file agree with the card files
+func (p *Parser) CheckIntegrity() *IntegrityReport {
+	report := &IntegrityReport{
+		Stacks: len(p.stacks),
+		Cards:  len(p.cards),
+		Issues: []IntegrityIssue{},
+	}
+
+	for _, stack := range p.stacks {
+		report.Issues = append(report.Issues, p.checkStackIntegrity(stack)...)
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Stack != b.Stack {
+			return a.Stack < b.Stack
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.CardID != b.CardID {
+			return a.CardID < b.CardID
+		}
+		return a.File < b.File
+	})
+
+	return report
+}
+
+// checkStackIntegrity compares the card list of a stack with its card files
+func (p *Parser) checkStackIntegrity(stack *HyperCardStack) []IntegrityIssue {
+	var issues []IntegrityIssue
+
+	addIssue := func(kind IntegrityIssueKind, cardID int, file, detail string) {
+		issues = append(issues, IntegrityIssue{
+			Kind:   kind,
+			Stack:  stack.Name,
+			CardID: cardID,
+			File:   file,
+			Detail: detail,
+		})
+	}
+
+	backgroundIDs := make(map[int]bool, len(stack.Backgrounds))
+	for _, background := range stack.Backgrounds {
+		backgroundIDs[background.ID] = true
+	}
+
+	// listed cards
+	listedIDs := make(map[int]bool, len(stack.CardsInfo))
+	listedFiles := make(map[string]bool, len(stack.CardsInfo))
+	for _, info := range stack.CardsInfo {
+		if listedIDs[info.ID] {
+			addIssue(DuplicateCardID, info.ID, info.File, fmt.Sprintf("card ID %d is listed more than once", info.ID))
+		}
+		listedIDs[info.ID] = true
+
+		file := info.File
+		if file == "" {
+			file = fmt.Sprintf("card_%d.xml", info.ID)
+		}
+		listedFiles[file] = true
+
+		if _, err := os.Stat(filepath.Join(stack.Directory, file)); err != nil {
+			addIssue(MissingCardFile, info.ID, file, fmt.Sprintf("listed card %d has no file", info.ID))
+		}
+
+		if info.Owner != 0 && !backgroundIDs[info.Owner] {
+			addIssue(MissingBackground, info.ID, file, fmt.Sprintf("owner background %d does not exist", info.Owner))
+		}
+	}
+
+	// card files
+	for _, card := range p.cards {
+		if card.Stack != stack {
+			continue
+		}
+
+		file := filepath.Base(card.Filepath)
+
+		if !listedFiles[file] {
+			addIssue(OrphanCardFile, card.ID, file, "card file is not listed in the stack")
+		}
+
+		if matches := cardFileIDPattern.FindStringSubmatch(file); matches != nil {
+			if fileID, err := strconv.Atoi(matches[1]); err == nil && fileID != card.ID {
+				addIssue(CardIDMismatch, card.ID, file, fmt.Sprintf("file ID %d differs from card ID %d", fileID, card.ID))
+			}
+		}
+	}
+
+	return issues
+}
+
+// CountByKind returns the number of issues of each kind
+func (r *IntegrityReport) CountByKind() map[IntegrityIssueKind]int {
+	counts := make(map[IntegrityIssueKind]int)
+	for _, issue := range r.Issues {
+		counts[issue.Kind]++
+	}
+	return counts
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *IntegrityReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/glthr/DeMystify/common"
 )
@@ -14,10 +15,90 @@ type HyperTalk struct {
 
 type ScriptLine struct {
 	Line       string
+	Number     int // 1-based line number in the script
 	IsDisabled bool
+	// Handler is the message handled by the enclosing handler (e.g., `mouseUp`)
+	Handler string
+	// HasVisualEffect reports that a `visual effect` of the same handler applies to the line
+	HasVisualEffect bool
+}
+
+// automaticHandlers are the messages sent by HyperCard without any player action
+var automaticHandlers = []string{
+	"openCard", "openBackground", "openStack", "closeCard", "closeBackground", "closeStack",
+	"resumeStack", "resume", "startUp", "idle",
+}
+
+var (
+	handlerStartPattern = regexp.MustCompile(`(?i)^on\s+(\w+)`)
+	handlerEndPattern   = regexp.MustCompile(`(?i)^end\s+\w+`)
+	visualEffectPattern = regexp.MustCompile(`(?i)^visual(\s+effect)?\b`)
+	goPattern           = regexp.MustCompile(`(?i)^go\b`)
+)
+
+// parseScript splits a script into lines, recording their handler and the visual effects
+// applying to them (a `visual effect` applies to the next `go` of the handler)
+func parseScript(rawScript string) HyperTalk {
+	var script HyperTalk
+	var handler string
+	visualEffect := false
+
+	for i, line := range splitScript(rawScript) {
+		line = strings.TrimSpace(line)
+		scriptLine := ScriptLine{
+			Line:       line,
+			Number:     i + 1,
+			IsDisabled: strings.HasPrefix(line, "--"),
+		}
+
+		if !scriptLine.IsDisabled {
+			switch {
+			case handlerStartPattern.MatchString(line):
+				handler = handlerStartPattern.FindStringSubmatch(line)[1]
+				visualEffect = false
+			case handlerEndPattern.MatchString(line):
+				handler = ""
+				visualEffect = false
+			case visualEffectPattern.MatchString(line):
+				visualEffect = true
+			}
+		}
+
+		scriptLine.Handler = handler
+		scriptLine.HasVisualEffect = visualEffect
+		if !scriptLine.IsDisabled && goPattern.MatchString(line) {
+			visualEffect = false
+		}
+
+		script.lines = append(script.lines, scriptLine)
+	}
+
+	return script
+}
+
+// isAutomatic reports whether the line belongs to a handler of a message
+// sent without any player action
+func (l ScriptLine) isAutomatic() bool {
+	for _, handler := range automaticHandlers {
+		if strings.EqualFold(l.Handler, handler) {
+			return true
+		}
+	}
+	return false
 }
 
 func (p *Parser) parseCardCommand(source any, command string) (*HyperCardLink, error) {
+	// NOTE: the node rules apply to any line, whether it holds a link or not
+	p.handleNodeRules(source, command)
+
+	link, err := p.parseLinkCommand(source, command)
+	if link != nil {
+		link.Tags = p.options.Rules.EdgeTags(command)
+	}
+	return link, err
+}
+
+func (p *Parser) parseLinkCommand(source any, command string) (*HyperCardLink, error) {
 	if link, err := p.handleGoToNamedCardOfStack(source, command); link != nil || err != nil {
 		return link, err
 	}
@@ -30,9 +111,6 @@ func (p *Parser) parseCardCommand(source any, command string) (*HyperCardLink, e
 	if handled := p.handlePopCard(source, command); handled {
 		return nil, nil
 	}
-	if handled := p.handlePagePatterns(source, command); handled {
-		return nil, nil
-	}
 	if link, err := p.handlePushCardOfStack(source, command); link != nil || err != nil {
 		return link, err
 	}
@@ -156,27 +234,17 @@ func (p *Parser) handlePopCard(source any, command string) bool {
 	return false
 }
 
-func (p *Parser) handlePagePatterns(source any, command string) bool {
-	bluePagePattern := regexp.MustCompile(`(?i)put "(\d+),A,0" into ALL_Page`)
-	redPagePattern := regexp.MustCompile(`(?i)put "(\d+),S,0" into ALL_Page`)
-	whitePagePattern := regexp.MustCompile(`(?i)put "Atrus" into ALL_Page`)
-
-	if matches := bluePagePattern.FindStringSubmatch(command); matches != nil {
-		source.(*HyperCardCard).HasBluePage = true
-		return true
-	}
-
-	if matches := redPagePattern.FindStringSubmatch(command); matches != nil {
-		source.(*HyperCardCard).HasRedPage = true
-		return true
+// handleNodeRules tags the card with the node rules matched by the command
+// (e.g., the Myst pages)
+func (p *Parser) handleNodeRules(source any, command string) {
+	card, ok := source.(*HyperCardCard)
+	if !ok {
+		return
 	}
 
-	if matches := whitePagePattern.FindStringSubmatch(command); matches != nil {
-		source.(*HyperCardCard).HasWhitePage = true
-		return true
+	if tags := p.options.Rules.NodeTags(command); len(tags) > 0 {
+		card.addTags(tags...)
 	}
-
-	return false
 }
 
 func (p *Parser) handlePushCardOfStack(source any, command string) (*HyperCardLink, error) {
@@ -238,7 +306,11 @@ func (p *Parser) createVirtualCard(stackName string, cardID any) (*HyperCardCard
 		return nil, err
 	}
 
+	// virtual cards referenced by name have no ID
+	id, _ := cardID.(int)
+
 	return &HyperCardCard{
+		ID: id,
 		Name: fmt.Sprintf("%s:%s",
 			stackName,
 			cardName,
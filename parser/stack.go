@@ -3,7 +3,9 @@ package parser
 import (
 	"encoding/xml"
 	"os"
-	"strings"
+	"path/filepath"
+
+	"github.com/glthr/DeMystify/common"
 )
 
 type HyperCardStack struct {
@@ -12,6 +14,13 @@ type HyperCardStack struct {
 	Script      []HyperTalk
 	Cards       []HyperCardCard
 	IsPushStack bool
+	CardsInfo   []CardInfo   // cards listed in the stack file
+	Backgrounds []Background // backgrounds listed in the stack file
+}
+
+// Filepath returns the path of the stack file
+func (s *HyperCardStack) Filepath() string {
+	return filepath.Join(s.Directory, stackFileName)
 }
 
 // Background is used to get the image name
@@ -31,45 +40,35 @@ type CardInfo struct {
 }
 
 // ParseStackFile parses a stack XML file and returns the HyperCard stack structure
-func ParseStackFile(filepath string) (*HyperCardStack, map[int]string, error) {
-	content, err := os.ReadFile(filepath)
+func ParseStackFile(path string) (*HyperCardStack, map[int]string, error) {
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, &common.ParseError{File: path, Err: err}
 	}
 
 	type stackInfo struct {
-		XMLName    xml.Name   `xml:"stack"`
-		Name       string     `xml:"name"`
-		ID         int        `xml:"id"`
-		CardCount  int        `xml:"cardCount"`
-		CardID     int        `xml:"cardID"`
-		ListID     int        `xml:"listID"`
-		CantModify bool       `xml:"cantModify,omitempty"`
-		CantDelete bool       `xml:"cantDelete,omitempty"`
-		CantAbort  bool       `xml:"cantAbort,omitempty"`
-		ScriptRaw  string     `xml:"script"`
-		Background Background `xml:"background"`
-		CardsInfo  []CardInfo `xml:"card"`
+		XMLName     xml.Name     `xml:"stack"`
+		Name        string       `xml:"name"`
+		ID          int          `xml:"id"`
+		CardCount   int          `xml:"cardCount"`
+		CardID      int          `xml:"cardID"`
+		ListID      int          `xml:"listID"`
+		CantModify  bool         `xml:"cantModify,omitempty"`
+		CantDelete  bool         `xml:"cantDelete,omitempty"`
+		CantAbort   bool         `xml:"cantAbort,omitempty"`
+		ScriptRaw   string       `xml:"script"`
+		Backgrounds []Background `xml:"background"`
+		CardsInfo   []CardInfo   `xml:"card"`
 	}
 
 	var s stackInfo
 	err = xml.Unmarshal(content, &s)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, newXMLError(path, err)
 	}
 
 	// process script for each part
-	scripts := make([]HyperTalk, 0, len(s.ScriptRaw))
-	var script HyperTalk
-	for _, line := range splitScript(s.ScriptRaw) {
-		line = strings.TrimSpace(line)
-		script.lines = append(script.lines, ScriptLine{
-			Line:       line,
-			IsDisabled: strings.HasPrefix(line, "--"),
-		})
-	}
-
-	scripts = append(scripts, script)
+	scripts := []HyperTalk{parseScript(s.ScriptRaw)}
 
 	// create a map associating the cards IDs with their names,
 	// as HyperCard cards do not contain their own names
@@ -81,8 +80,11 @@ func ParseStackFile(filepath string) (*HyperCardStack, map[int]string, error) {
 	}
 
 	return &HyperCardStack{
-			Name:   s.Name,
-			Script: scripts,
+			Directory:   filepath.Dir(path),
+			Name:        s.Name,
+			Script:      scripts,
+			CardsInfo:   s.CardsInfo,
+			Backgrounds: s.Backgrounds,
 		},
 		cardsIdNameMap,
 		nil
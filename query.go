@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/glthr/DeMystify/config"
+	"github.com/glthr/DeMystify/graph"
+	"github.com/glthr/DeMystify/query"
+	"github.com/glthr/DeMystify/renderer/dot"
+)
+
+// queryOverlayStyle highlights the results of a query
+var queryOverlayStyle = config.Style{Color: "#E53935", PenWidth: 4}
+
+const queryHelp = `Queries:
+  nodes [where <condition>] [limit <n>]
+  edges [where <condition>] [limit <n>]
+  [path] <node> -[<condition>]-> <node> -> <node>... [limit <n>]
+    <node>: "<card name>", (<condition>), or () for any node
+Conditions (and, or, not, parentheses):
+  <attribute>                   e.g., IsSink, ContainsBluePage, CrossAge, Disabled
+  <field> <op> <value>          ops: = != < <= > >= ~ (case-insensitive regular expression)
+    node fields: name, stack, background, tag, id, indegree, outdegree, degree
+    edge fields: tag, from.<node field or attribute>, to.<node field or attribute>
+Commands:
+  :format table|json   set the output format
+  :render              render the last result in ` + queryPath + `
+  :help                show this help
+  :quit                exit`
+
+// runQuery evaluates a query on the Myst Graph (-e), or starts an interactive session
+func runQuery(args []string) {
+	flags := flag.NewFlagSet(queryCommand, flag.ExitOnError)
+	graphOptions := registerGraphFlags(flags)
+	expression := flags.String("e", "", "evaluate the `query` and exit (default: interactive session)")
+	format := flags.String("format", "table", "output format: `table` or json")
+	render := flags.Bool("render", false, "with -e, render the result in "+queryPath)
+	_ = flags.Parse(args)
+
+	if flags.NArg() < 1 {
+		log.Fatal("Usage: go run . query [-profile <name|profile.json>] [-rules <rules.json>] [-continue-on-error] [-cost-model <preset>] [-no-cache] [-e <query> [-render]] [-format table|json] <xml_hypercard_files_directory_path>")
+	}
+
+	if *format != "table" && *format != "json" {
+		log.Fatalf("unknown output format: %s", *format)
+	}
+
+	if *render {
+		if err := CheckNeatoInstalled(); err != nil {
+			log.Fatalf("Neato is not installed: %v", err)
+		}
+	}
+
+	loaded := loadGraph(flags.Arg(0), graphOptions, graph.DefaultCycleOptions())
+
+	if *expression != "" {
+		result, err := evaluateQuery(loaded.graph, *expression)
+		if err != nil {
+			log.Fatalf("invalid query: %v", err)
+		}
+
+		if err := result.Write(os.Stdout, *format); err != nil {
+			log.Fatalf("error writing the result: %v", err)
+		}
+
+		if *render {
+			renderQueryResult(loaded, *expression, result)
+		}
+	} else {
+		runQuerySession(loaded, *format)
+	}
+
+	if loaded.parseErrors.HasErrors() {
+		os.Exit(exitPartialSuccess)
+	}
+}
+
+// runQuerySession reads and evaluates the queries of the standard input, one per line
+func runQuerySession(loaded *loadedGraph, format string) {
+	fmt.Println(`Type a query, or :help`)
+
+	var lastQuery string
+	var lastResult *query.Result
+
+	scanner := bufio.NewScanner(os.Stdin)
+	for {
+		fmt.Print("demystify> ")
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		command, argument, _ := strings.Cut(line, " ")
+
+		switch command {
+		case "":
+		case ":quit", ":q":
+			return
+		case ":help":
+			fmt.Println(queryHelp)
+		case ":format":
+			if argument = strings.TrimSpace(argument); argument != "table" && argument != "json" {
+				fmt.Println("expected :format table or :format json")
+				continue
+			}
+			format = argument
+		case ":render":
+			if lastResult == nil {
+				fmt.Println("no result to render")
+				continue
+			}
+			if err := CheckNeatoInstalled(); err != nil {
+				fmt.Printf("Neato is not installed: %v\n", err)
+				continue
+			}
+			renderQueryResult(loaded, lastQuery, lastResult)
+		default:
+			result, err := evaluateQuery(loaded.graph, line)
+			if err != nil {
+				fmt.Println(err)
+				continue
+			}
+
+			if err := result.Write(os.Stdout, format); err != nil {
+				fmt.Printf("error writing the result: %v\n", err)
+			}
+			lastQuery, lastResult = line, result
+		}
+	}
+}
+
+// evaluateQuery parses and evaluates a query
+func evaluateQuery(g *graph.MystGraph, expression string) (*query.Result, error) {
+	q, err := query.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	return query.Run(g, q), nil
+}
+
+// renderQueryResult renders the graph, the nodes and edges of the result highlighted
+func renderQueryResult(loaded *loadedGraph, expression string, result *query.Result) {
+	fmt.Println("Generating the query rendering...")
+
+	dotConfig := dot.DefaultConfig()
+	dotConfig.TagStyles = loaded.profile.Rules.Styles
+	dotConfig.StackDisplayNames = loaded.profile.StackDisplayNames
+	dotConfig.Overlays = []dot.Overlay{{
+		Name:  expression,
+		Paths: result.EdgePaths(),
+		Nodes: result.NodeIDs(),
+		Style: queryOverlayStyle,
+	}}
+
+	RenderAlternateGraph(loaded.graph, loaded.metadata, dotConfig, queryPath)
+}
@@ -0,0 +1,195 @@
+package query
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/graph"
+)
+
+// DefaultPathLimit is the maximum number of paths returned when the query sets no limit
+const DefaultPathLimit = 1000
+
+// evaluator evaluates the queries on a graph
+type evaluator struct {
+	g *graph.MystGraph
+	// source node ID -> edges (path queries)
+	outgoing map[int64][]*common.Edge
+}
+
+// Run evaluates a query on an analyzed graph
+// NOTE: the queries see all the links, including the disabled ones (see the Disabled attribute)
+func Run(g *graph.MystGraph, q *Query) *Result {
+	e := &evaluator{g: g}
+
+	switch q.Kind {
+	case NodesQuery:
+		return e.nodes(q)
+	case EdgesQuery:
+		return e.edges(q)
+	default:
+		return e.paths(q)
+	}
+}
+
+func (e *evaluator) nodes(q *Query) *Result {
+	result := &Result{Kind: NodesQuery}
+
+	for _, node := range e.sortedNodes() {
+		if q.where != nil && !q.where(e, subject{node: node}) {
+			continue
+		}
+		if q.Limit > 0 && len(result.Nodes) == q.Limit {
+			result.Truncated = true
+			break
+		}
+		result.Nodes = append(result.Nodes, node)
+	}
+
+	return result
+}
+
+func (e *evaluator) edges(q *Query) *Result {
+	result := &Result{Kind: EdgesQuery}
+
+	edges := slices.Clone(e.g.Metadata.Edges)
+	slices.SortStableFunc(edges, func(a, b *common.Edge) int {
+		if c := compareNodes(a.Source, b.Source); c != 0 {
+			return c
+		}
+		return compareNodes(a.Target, b.Target)
+	})
+
+	for _, edge := range edges {
+		if q.where != nil && !q.where(e, subject{edge: edge}) {
+			continue
+		}
+		if q.Limit > 0 && len(result.Edges) == q.Limit {
+			result.Truncated = true
+			break
+		}
+		result.Edges = append(result.Edges, edge)
+	}
+
+	return result
+}
+
+// paths enumerates the paths matching the pattern, in depth-first order
+// NOTE: a path does not visit the same node twice, and the parallel edges
+// matching a hop yield a single path
+func (e *evaluator) paths(q *Query) *Result {
+	result := &Result{Kind: PathQuery}
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPathLimit
+	}
+
+	e.outgoing = make(map[int64][]*common.Edge)
+	for _, edge := range e.g.Metadata.Edges {
+		e.outgoing[edge.Source.GraphID] = append(e.outgoing[edge.Source.GraphID], edge)
+	}
+	for _, edges := range e.outgoing {
+		slices.SortStableFunc(edges, func(a, b *common.Edge) int {
+			return compareNodes(a.Target, b.Target)
+		})
+	}
+
+	var path []*common.Node
+	onPath := make(map[int64]bool)
+
+	var search func(step int) bool
+	search = func(step int) bool {
+		if step == len(q.steps)-1 {
+			if len(result.Paths) == limit {
+				result.Truncated = true
+				return false
+			}
+			result.Paths = append(result.Paths, slices.Clone(path))
+			return true
+		}
+
+		current := path[len(path)-1]
+		var previous *common.Node
+		for _, edge := range e.outgoing[current.GraphID] {
+			next := edge.Target
+			if next == previous || onPath[next.GraphID] {
+				continue
+			}
+			if !e.matches(q.hops[step], subject{edge: edge}) || !e.matches(q.steps[step+1], subject{node: next}) {
+				continue
+			}
+			previous = next
+
+			path = append(path, next)
+			onPath[next.GraphID] = true
+			more := search(step + 1)
+			onPath[next.GraphID] = false
+			path = path[:len(path)-1]
+
+			if !more {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for _, node := range e.sortedNodes() {
+		if !e.matches(q.steps[0], subject{node: node}) {
+			continue
+		}
+
+		path = append(path[:0], node)
+		onPath[node.GraphID] = true
+		more := search(0)
+		onPath[node.GraphID] = false
+
+		if !more {
+			break
+		}
+	}
+
+	return result
+}
+
+// matches evaluates a condition (nil conditions match everything)
+func (e *evaluator) matches(c condition, s subject) bool {
+	return c == nil || c(e, s)
+}
+
+// sortedNodes returns the nodes sorted by stack and name
+func (e *evaluator) sortedNodes() []*common.Node {
+	nodes := slices.Clone(e.g.Metadata.Nodes)
+	slices.SortStableFunc(nodes, compareNodes)
+	return nodes
+}
+
+func compareNodes(a, b *common.Node) int {
+	if c := strings.Compare(a.StackName, b.StackName); c != 0 {
+		return c
+	}
+	if c := strings.Compare(a.Name, b.Name); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.GraphID, b.GraphID)
+}
+
+// inDegree returns the number of predecessors of a node (as in the centrality rankings)
+func (e *evaluator) inDegree(id int64) int {
+	return e.g.Metadata.Stats.Centrality.Nodes[id].InDegree
+}
+
+// outDegree returns the number of successors of a node (as in the centrality rankings)
+func (e *evaluator) outDegree(id int64) int {
+	return e.g.Metadata.Stats.Centrality.Nodes[id].OutDegree
+}
+
+func (e *evaluator) stackDisplayName(stack string) string {
+	if e.g.Profile == nil {
+		return stack
+	}
+	return e.g.Profile.StackDisplayName(stack)
+}
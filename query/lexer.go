@@ -0,0 +1,121 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+type tokenKind int
+
+const (
+	endToken        tokenKind = iota
+	identToken                // keyword, field, or attribute name (e.g., `stack`, `CrossAge`)
+	stringToken               // "quoted value"
+	numberToken               // 42
+	operatorToken             // = != < <= > >= ~
+	openParenToken            // (
+	closeParenToken           // )
+	arrowToken                // ->
+	openArrowToken            // -[
+	closeArrowToken           // ]->
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value string // unquoted value of the strings
+	pos   int    // 1-based position in the query
+}
+
+func (t token) String() string {
+	if t.kind == endToken {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is reports whether the token is the given keyword (case-insensitive)
+func (t token) is(keyword string) bool {
+	return t.kind == identToken && strings.EqualFold(t.text, keyword)
+}
+
+// syntaxError returns an error located at the given token
+func syntaxError(t token, format string, args ...any) error {
+	return fmt.Errorf("%w at position %d: %s", common.QuerySyntaxErr, t.pos, fmt.Sprintf(format, args...))
+}
+
+// tokenize splits a query into tokens
+func tokenize(input string) ([]token, error) {
+	var tokens []token
+	runes := []rune(input)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '"':
+			var value strings.Builder
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+				i++
+			}
+			if i == len(runes) {
+				return nil, syntaxError(token{pos: start + 1}, "unterminated string")
+			}
+			i++
+			tokens = append(tokens, token{kind: stringToken, text: string(runes[start:i]), value: value.String(), pos: start + 1})
+			continue
+		case unicode.IsDigit(r):
+			for i < len(runes) && unicode.IsDigit(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: numberToken, text: string(runes[start:i]), pos: start + 1})
+			continue
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_' || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, token{kind: identToken, text: string(runes[start:i]), pos: start + 1})
+			continue
+		}
+
+		// punctuation and operators (longest match first)
+		rest := string(runes[i:])
+		var kind tokenKind
+		var text string
+		switch {
+		case strings.HasPrefix(rest, "]->"):
+			kind, text = closeArrowToken, "]->"
+		case strings.HasPrefix(rest, "-["):
+			kind, text = openArrowToken, "-["
+		case strings.HasPrefix(rest, "->"):
+			kind, text = arrowToken, "->"
+		case strings.HasPrefix(rest, "!="), strings.HasPrefix(rest, "<="), strings.HasPrefix(rest, ">="):
+			kind, text = operatorToken, rest[:2]
+		case r == '=' || r == '<' || r == '>' || r == '~':
+			kind, text = operatorToken, string(r)
+		case r == '(':
+			kind, text = openParenToken, "("
+		case r == ')':
+			kind, text = closeParenToken, ")"
+		default:
+			return nil, syntaxError(token{pos: start + 1}, "unexpected character %q", r)
+		}
+
+		tokens = append(tokens, token{kind: kind, text: text, pos: start + 1})
+		i += len([]rune(text))
+	}
+
+	return append(tokens, token{kind: endToken, pos: len(runes) + 1}), nil
+}
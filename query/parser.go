@@ -0,0 +1,469 @@
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// Kind is the kind of result of a query
+type Kind string
+
+const (
+	NodesQuery Kind = "nodes" // `nodes [where <condition>]`
+	EdgesQuery Kind = "edges" // `edges [where <condition>]`
+	PathQuery  Kind = "paths" // `[path] <node> -[<condition>]-> <node> -> <node>...`
+)
+
+// Query is a parsed query (see Parse)
+type Query struct {
+	Kind  Kind
+	Limit int // maximum number of results (no limit if 0)
+
+	where condition   // nodes and edges queries
+	steps []condition // path queries: the nodes (nil matches any node)...
+	hops  []condition // ... and the edges between them (nil matches any edge)
+}
+
+// subject is what a condition is evaluated on: a node, or an edge
+type subject struct {
+	node *common.Node
+	edge *common.Edge
+}
+
+// condition is a compiled condition
+type condition func(e *evaluator, s subject) bool
+
+// context is the kind of subject of the conditions being parsed
+type context int
+
+const (
+	nodeContext context = iota
+	edgeContext
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse parses a query:
+//
+//	nodes [where <condition>] [limit <n>]
+//	edges [where <condition>] [limit <n>]
+//	[path] <node> -[<condition>]-> <node> -> <node>... [limit <n>]
+//
+// where a path node is a card name ("Myst:8336") or a condition in parentheses (`()` matches any node)
+func Parse(input string) (*Query, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+
+	var q *Query
+	switch current := p.peek(); {
+	case current.is("nodes"):
+		p.next()
+		q, err = p.parseFilter(NodesQuery, nodeContext)
+	case current.is("edges"):
+		p.next()
+		q, err = p.parseFilter(EdgesQuery, edgeContext)
+	case current.is("path"):
+		p.next()
+		q, err = p.parsePath()
+	case current.kind == openParenToken || current.kind == stringToken:
+		q, err = p.parsePath()
+	default:
+		return nil, syntaxError(current, "expected nodes, edges, or a path, found %s", current)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.peek().is("limit") {
+		p.next()
+		limit := p.next()
+		if limit.kind != numberToken {
+			return nil, syntaxError(limit, "expected a number after limit, found %s", limit)
+		}
+		q.Limit, _ = strconv.Atoi(limit.text)
+	}
+
+	if end := p.peek(); end.kind != endToken {
+		return nil, syntaxError(end, "unexpected %s", end)
+	}
+
+	return q, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != endToken {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) expect(kind tokenKind, expected string) error {
+	if t := p.next(); t.kind != kind {
+		return syntaxError(t, "expected %s, found %s", expected, t)
+	}
+	return nil
+}
+
+// parseFilter parses the optional `where` clause of a nodes or edges query
+func (p *parser) parseFilter(kind Kind, ctx context) (*Query, error) {
+	q := &Query{Kind: kind}
+
+	if p.peek().is("where") {
+		p.next()
+		where, err := p.parseOr(ctx)
+		if err != nil {
+			return nil, err
+		}
+		q.where = where
+	}
+
+	return q, nil
+}
+
+// parsePath parses a path pattern: nodes linked by arrows
+func (p *parser) parsePath() (*Query, error) {
+	q := &Query{Kind: PathQuery}
+
+	step, err := p.parseStep()
+	if err != nil {
+		return nil, err
+	}
+	q.steps = append(q.steps, step)
+
+	for {
+		var hop condition
+		switch current := p.peek(); current.kind {
+		case arrowToken:
+			p.next()
+		case openArrowToken:
+			p.next()
+			if p.peek().kind != closeArrowToken {
+				if hop, err = p.parseOr(edgeContext); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.expect(closeArrowToken, "]->"); err != nil {
+				return nil, err
+			}
+		default:
+			if len(q.steps) < 2 {
+				return nil, syntaxError(current, "expected -> or -[...]->, found %s", current)
+			}
+			return q, nil
+		}
+
+		if step, err = p.parseStep(); err != nil {
+			return nil, err
+		}
+		q.hops = append(q.hops, hop)
+		q.steps = append(q.steps, step)
+	}
+}
+
+// parseStep parses a node of a path: a card name, or a condition in parentheses
+func (p *parser) parseStep() (condition, error) {
+	switch current := p.next(); current.kind {
+	case stringToken:
+		name := current.value
+		return func(_ *evaluator, s subject) bool {
+			return strings.EqualFold(s.node.Name, name)
+		}, nil
+	case openParenToken:
+		if p.peek().kind == closeParenToken {
+			p.next()
+			return nil, nil
+		}
+		step, err := p.parseOr(nodeContext)
+		if err != nil {
+			return nil, err
+		}
+		return step, p.expect(closeParenToken, ")")
+	default:
+		return nil, syntaxError(current, "expected a card name or (...), found %s", current)
+	}
+}
+
+func (p *parser) parseOr(ctx context) (condition, error) {
+	left, err := p.parseAnd(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().is("or") {
+		p.next()
+		right, err := p.parseAnd(ctx)
+		if err != nil {
+			return nil, err
+		}
+		left = or(left, right)
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd(ctx context) (condition, error) {
+	left, err := p.parseNot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().is("and") {
+		p.next()
+		right, err := p.parseNot(ctx)
+		if err != nil {
+			return nil, err
+		}
+		left = and(left, right)
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseNot(ctx context) (condition, error) {
+	if p.peek().is("not") {
+		p.next()
+		operand, err := p.parseNot(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return func(e *evaluator, s subject) bool { return !operand(e, s) }, nil
+	}
+
+	if p.peek().kind == openParenToken {
+		p.next()
+		inner, err := p.parseOr(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return inner, p.expect(closeParenToken, ")")
+	}
+
+	return p.parsePredicate(ctx)
+}
+
+// parsePredicate parses an attribute name (e.g., `CrossAge`) or a comparison (e.g., `indegree > 3`)
+// NOTE: in the edge conditions, the `from.` and `to.` prefixes apply a node predicate
+// to the source or target node (e.g., `to.ContainsBluePage`)
+func (p *parser) parsePredicate(ctx context) (condition, error) {
+	field := p.next()
+	if field.kind != identToken {
+		return nil, syntaxError(field, "expected an attribute or a field, found %s", field)
+	}
+
+	name := field.text
+	if ctx == edgeContext {
+		if prefix, rest, found := strings.Cut(name, "."); found {
+			var endpoint func(*common.Edge) *common.Node
+			switch strings.ToLower(prefix) {
+			case "from":
+				endpoint = func(edge *common.Edge) *common.Node { return edge.Source }
+			case "to":
+				endpoint = func(edge *common.Edge) *common.Node { return edge.Target }
+			default:
+				return nil, syntaxError(field, "unknown prefix %q (expected from or to)", prefix)
+			}
+
+			nodePredicate, err := p.parseField(field, rest, nodeContext)
+			if err != nil {
+				return nil, err
+			}
+			return func(e *evaluator, s subject) bool {
+				return nodePredicate(e, subject{node: endpoint(s.edge)})
+			}, nil
+		}
+	}
+
+	return p.parseField(field, name, ctx)
+}
+
+// parseField parses the rest of a predicate on the given field
+func (p *parser) parseField(field token, name string, ctx context) (condition, error) {
+	if p.peek().kind != operatorToken {
+		return attributePredicate(field, name, ctx)
+	}
+
+	operator := p.next()
+	value := p.next()
+	if value.kind != stringToken && value.kind != numberToken {
+		return nil, syntaxError(value, "expected a value, found %s", value)
+	}
+	if value.kind == numberToken {
+		value.value = value.text
+	}
+
+	switch lowerName := strings.ToLower(name); {
+	case lowerName == "tag":
+		return tagPredicate(operator, value, ctx)
+	case ctx == nodeContext && (lowerName == "name" || lowerName == "stack" || lowerName == "background"):
+		return stringPredicate(operator, value, lowerName)
+	case ctx == nodeContext && (lowerName == "id" || lowerName == "indegree" || lowerName == "outdegree" || lowerName == "degree"):
+		return numberPredicate(operator, value, lowerName)
+	default:
+		return nil, syntaxError(field, "unknown field %q", name)
+	}
+}
+
+// attributePredicate matches the nodes or edges with the given attribute
+func attributePredicate(field token, name string, ctx context) (condition, error) {
+	if ctx == nodeContext {
+		attribute, ok := common.ParseNodeAttribute(name)
+		if !ok {
+			return nil, syntaxError(field, "unknown node attribute %q", name)
+		}
+		return func(_ *evaluator, s subject) bool { return s.node.IsOfType(attribute) }, nil
+	}
+
+	attribute, ok := common.ParseEdgeAttribute(name)
+	if !ok {
+		return nil, syntaxError(field, "unknown edge attribute %q", name)
+	}
+	return func(_ *evaluator, s subject) bool { return s.edge.IsOfType(attribute) }, nil
+}
+
+// tagPredicate matches the nodes or edges with (=), without (!=), or with a tag matching (~) the value
+func tagPredicate(operator, value token, ctx context) (condition, error) {
+	tags := func(s subject) []string {
+		if ctx == nodeContext {
+			return s.node.Tags
+		}
+		return s.edge.Tags
+	}
+
+	match, err := matcher(operator, value)
+	if err != nil {
+		return nil, err
+	}
+
+	if operator.text == "!=" {
+		return func(_ *evaluator, s subject) bool {
+			for _, tag := range tags(s) {
+				if strings.EqualFold(tag, value.value) {
+					return false
+				}
+			}
+			return true
+		}, nil
+	}
+
+	return func(_ *evaluator, s subject) bool {
+		for _, tag := range tags(s) {
+			if match(tag) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// stringPredicate compares the name, the stack (or its display name), or the background of the nodes
+func stringPredicate(operator, value token, field string) (condition, error) {
+	match, err := matcher(operator, value)
+	if err != nil {
+		return nil, err
+	}
+
+	values := func(e *evaluator, node *common.Node) []string {
+		switch field {
+		case "name":
+			return []string{node.Name}
+		case "stack":
+			return []string{node.StackName, e.stackDisplayName(node.StackName)}
+		default:
+			if node.SecondaryName == nil {
+				return nil
+			}
+			return []string{*node.SecondaryName}
+		}
+	}
+
+	return func(e *evaluator, s subject) bool {
+		matched := false
+		for _, v := range values(e, s.node) {
+			if match(v) {
+				matched = true
+				break
+			}
+		}
+		// NOTE: `!=` holds if no value is equal
+		return matched != (operator.text == "!=")
+	}, nil
+}
+
+// matcher returns the string comparison of an operator: case-insensitive equality (= and !=)
+// or regular expression (~)
+func matcher(operator, value token) (func(string) bool, error) {
+	switch operator.text {
+	case "=", "!=":
+		return func(s string) bool { return strings.EqualFold(s, value.value) }, nil
+	case "~":
+		re, err := regexp.Compile("(?i)" + value.value)
+		if err != nil {
+			return nil, syntaxError(value, "invalid regular expression: %v", err)
+		}
+		return re.MatchString, nil
+	default:
+		return nil, syntaxError(operator, "operator %s does not apply to text", operator.text)
+	}
+}
+
+// numberPredicate compares the card ID or the degrees of the nodes
+func numberPredicate(operator, value token, field string) (condition, error) {
+	expected, err := strconv.Atoi(value.value)
+	if err != nil {
+		return nil, syntaxError(value, "expected a number, found %s", value)
+	}
+
+	var compare func(int) bool
+	switch operator.text {
+	case "=":
+		compare = func(n int) bool { return n == expected }
+	case "!=":
+		compare = func(n int) bool { return n != expected }
+	case "<":
+		compare = func(n int) bool { return n < expected }
+	case "<=":
+		compare = func(n int) bool { return n <= expected }
+	case ">":
+		compare = func(n int) bool { return n > expected }
+	case ">=":
+		compare = func(n int) bool { return n >= expected }
+	default:
+		return nil, syntaxError(operator, "operator %s does not apply to numbers", operator.text)
+	}
+
+	return func(e *evaluator, s subject) bool {
+		switch field {
+		case "id":
+			return compare(s.node.CardID)
+		case "indegree":
+			return compare(e.inDegree(s.node.GraphID))
+		case "outdegree":
+			return compare(e.outDegree(s.node.GraphID))
+		default:
+			return compare(e.inDegree(s.node.GraphID) + e.outDegree(s.node.GraphID))
+		}
+	}, nil
+}
+
+func and(left, right condition) condition {
+	return func(e *evaluator, s subject) bool { return left(e, s) && right(e, s) }
+}
+
+func or(left, right condition) condition {
+	return func(e *evaluator, s subject) bool { return left(e, s) || right(e, s) }
+}
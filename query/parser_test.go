@@ -0,0 +1,165 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+func TestParsePrecedence(t *testing.T) {
+	tests := []struct {
+		where    string
+		expected func(source, sink, entry bool) bool
+	}{
+		{"IsSource or IsSink and IsEntry", func(a, b, c bool) bool { return a || b && c }},
+		{"IsSource and IsSink or IsEntry", func(a, b, c bool) bool { return a && b || c }},
+		{"(IsSource or IsSink) and IsEntry", func(a, b, c bool) bool { return (a || b) && c }},
+		{"not IsSource and IsSink", func(a, b, c bool) bool { return !a && b }},
+		{"not (IsSource and IsSink)", func(a, b, c bool) bool { return !(a && b) }},
+		{"not not IsSource or IsSink", func(a, b, c bool) bool { return a || b }},
+		{"IsSource and not IsSink or not IsEntry", func(a, b, c bool) bool { return a && !b || !c }},
+		{"IsSource or IsSink or IsEntry and not IsSource", func(a, b, c bool) bool { return a || b || c && !a }},
+	}
+
+	for _, test := range tests {
+		t.Run(test.where, func(t *testing.T) {
+			q, err := Parse("nodes where " + test.where)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// every combination of the three attributes
+			for combination := range 8 {
+				source, sink, entry := combination&1 != 0, combination&2 != 0, combination&4 != 0
+
+				node := &common.Node{}
+				for attribute, set := range map[common.NodeAttribute]bool{common.IsSource: source, common.IsSink: sink, common.IsEntry: entry} {
+					if set {
+						node.Attributes = append(node.Attributes, attribute)
+					}
+				}
+
+				if got, expected := q.where(nil, subject{node: node}), test.expected(source, sink, entry); got != expected {
+					t.Errorf("source=%v sink=%v entry=%v: got %v, expected %v", source, sink, entry, got, expected)
+				}
+			}
+		})
+	}
+}
+
+func TestParseEndpointPrefixes(t *testing.T) {
+	entry := &common.Node{Name: "Myst:8336", Attributes: []common.NodeAttribute{common.IsCard, common.IsEntry}}
+	page := &common.Node{Name: "Myst:8337", Attributes: []common.NodeAttribute{common.IsCard}, Tags: []string{"BluePage"}}
+
+	forward := &common.Edge{Source: entry, Target: page, Attributes: []common.EdgeAttribute{common.IntraAge}}
+	backward := &common.Edge{Source: page, Target: entry, Attributes: []common.EdgeAttribute{common.IntraAge, common.Disabled}}
+
+	tests := []struct {
+		where    string
+		forward  bool
+		backward bool
+	}{
+		{"from.IsEntry", true, false},
+		{"to.IsEntry", false, true},
+		{"FROM.IsEntry", true, false},
+		{`to.tag = "bluepage"`, true, false},
+		{`from.name = "Myst:8337"`, false, true},
+		{`to.name ~ "^myst:"`, true, true},
+		{"from.IsEntry or to.IsEntry", true, true},
+		{"Disabled and to.IsEntry", false, true},
+		{"not Disabled and not from.IsEntry", false, false},
+		{"IntraAge and (from.IsEntry or Disabled)", true, true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.where, func(t *testing.T) {
+			q, err := Parse("edges where " + test.where)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := q.where(nil, subject{edge: forward}); got != test.forward {
+				t.Errorf("%s -> %s: got %v, expected %v", forward.Source.Name, forward.Target.Name, got, test.forward)
+			}
+			if got := q.where(nil, subject{edge: backward}); got != test.backward {
+				t.Errorf("%s -> %s: got %v, expected %v", backward.Source.Name, backward.Target.Name, got, test.backward)
+			}
+		})
+	}
+}
+
+func TestParsePaths(t *testing.T) {
+	tests := []struct {
+		query string
+		steps int
+		limit int
+	}{
+		{`"Myst:8336" -> "Myst:8337"`, 2, 0},
+		{`path "Myst:8336" -[CrossAge]-> () -> (IsGoal) limit 3`, 3, 3},
+		{`(IsEntry) -[]-> (not IsVirtual)`, 2, 0},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := Parse(test.query)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if q.Kind != PathQuery || len(q.steps) != test.steps || len(q.hops) != test.steps-1 || q.Limit != test.limit {
+				t.Errorf("got %s with %d step(s), %d hop(s), and the limit %d", q.Kind, len(q.steps), len(q.hops), q.Limit)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query    string
+		position int
+		message  string
+	}{
+		{"", 1, "expected nodes, edges, or a path"},
+		{"cards", 1, "expected nodes, edges, or a path"},
+		{"nodes where", 12, "expected an attribute or a field"},
+		{"nodes where IsBogus", 13, `unknown node attribute "IsBogus"`},
+		{"nodes where IsSource and", 25, "expected an attribute or a field"},
+		{"nodes where (IsSource or IsSink", 32, "expected ), found end of query"},
+		{"nodes where from.IsEntry", 13, `unknown node attribute "from.IsEntry"`},
+		{"edges where up.IsEntry", 13, `unknown prefix "up"`},
+		{"edges where to.IsBogus", 13, `unknown node attribute "IsBogus"`},
+		{"edges where from.indegree ~ 3", 27, "operator ~ does not apply to numbers"},
+		{`nodes where name < "Myst"`, 18, "operator < does not apply to text"},
+		{`nodes where name ~ "("`, 20, "invalid regular expression"},
+		{`nodes where name = "Myst`, 20, "unterminated string"},
+		{"nodes where level = 3", 13, `unknown field "level"`},
+		{"nodes where IsSource IsSink", 22, `unexpected "IsSink"`},
+		{"nodes limit many", 13, "expected a number after limit"},
+		{"nodes where stack = $", 21, "unexpected character '$'"},
+		{`"Myst:8336"`, 12, "expected -> or -[...]->"},
+		{`"Myst:8336" -> IsGoal`, 16, "expected a card name or (...)"},
+		{`"Myst:8336" -[CrossAge "Myst:8337"`, 24, "expected ]->"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			_, err := Parse(test.query)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			if !errors.Is(err, common.QuerySyntaxErr) {
+				t.Errorf("got %v, expected a query syntax error", err)
+			}
+			if position := fmt.Sprintf("at position %d:", test.position); !strings.Contains(err.Error(), position) {
+				t.Errorf("got %q, expected the position %d", err, test.position)
+			}
+			if !strings.Contains(err.Error(), test.message) {
+				t.Errorf("got %q, expected %q", err, test.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// Result contains the nodes, the edges, or the paths matching a query
+type Result struct {
+	Kind  Kind
+	Nodes []*common.Node
+	Edges []*common.Edge
+	Paths [][]*common.Node
+	// Truncated reports that the limit was reached (more results exist)
+	Truncated bool
+}
+
+// Len returns the number of results
+func (r *Result) Len() int {
+	switch r.Kind {
+	case NodesQuery:
+		return len(r.Nodes)
+	case EdgesQuery:
+		return len(r.Edges)
+	default:
+		return len(r.Paths)
+	}
+}
+
+// NodeIDs returns the IDs of the matching nodes (and of the nodes of the matching edges and paths)
+func (r *Result) NodeIDs() []int64 {
+	var ids []int64
+	seen := make(map[int64]bool)
+	add := func(node *common.Node) {
+		if !seen[node.GraphID] {
+			seen[node.GraphID] = true
+			ids = append(ids, node.GraphID)
+		}
+	}
+
+	for _, node := range r.Nodes {
+		add(node)
+	}
+	for _, edge := range r.Edges {
+		add(edge.Source)
+		add(edge.Target)
+	}
+	for _, path := range r.Paths {
+		for _, node := range path {
+			add(node)
+		}
+	}
+
+	return ids
+}
+
+// EdgePaths returns the matching edges and paths as paths of node IDs
+func (r *Result) EdgePaths() [][]int64 {
+	var paths [][]int64
+	for _, edge := range r.Edges {
+		paths = append(paths, edge.GetSourceAndTargetIDs())
+	}
+	for _, path := range r.Paths {
+		ids := make([]int64, len(path))
+		for i, node := range path {
+			ids[i] = node.GraphID
+		}
+		paths = append(paths, ids)
+	}
+	return paths
+}
+
+// nodeRecord is the exported representation of a node
+type nodeRecord struct {
+	ID         int64    `json:"id"`
+	Name       string   `json:"name"`
+	Stack      string   `json:"stack"`
+	CardID     int      `json:"cardId,omitempty"`
+	Background string   `json:"background,omitempty"`
+	Attributes []string `json:"attributes"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// edgeRecord is the exported representation of an edge
+type edgeRecord struct {
+	From       string   `json:"from"`
+	To         string   `json:"to"`
+	FromID     int64    `json:"fromId"`
+	ToID       int64    `json:"toId"`
+	Attributes []string `json:"attributes"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// resultRecord is the exported representation of a result
+type resultRecord struct {
+	Kind      Kind         `json:"kind"`
+	Count     int          `json:"count"`
+	Truncated bool         `json:"truncated"`
+	Nodes     []nodeRecord `json:"nodes,omitempty"`
+	Edges     []edgeRecord `json:"edges,omitempty"`
+	Paths     [][]string   `json:"paths,omitempty"`
+}
+
+func newNodeRecord(node *common.Node) nodeRecord {
+	record := nodeRecord{
+		ID:         node.GraphID,
+		Name:       node.Name,
+		Stack:      node.StackName,
+		CardID:     node.CardID,
+		Attributes: make([]string, 0, len(node.Attributes)),
+		Tags:       node.Tags,
+	}
+	if node.SecondaryName != nil {
+		record.Background = *node.SecondaryName
+	}
+	for _, attr := range node.Attributes {
+		record.Attributes = append(record.Attributes, attr.String())
+	}
+	return record
+}
+
+func newEdgeRecord(edge *common.Edge) edgeRecord {
+	record := edgeRecord{
+		From:       edge.Source.Name,
+		To:         edge.Target.Name,
+		FromID:     edge.Source.GraphID,
+		ToID:       edge.Target.GraphID,
+		Attributes: make([]string, 0, len(edge.Attributes)),
+		Tags:       edge.Tags,
+	}
+	for _, attr := range edge.Attributes {
+		record.Attributes = append(record.Attributes, attr.String())
+	}
+	return record
+}
+
+func pathNames(path []*common.Node) []string {
+	names := make([]string, len(path))
+	for i, node := range path {
+		names[i] = node.Name
+	}
+	return names
+}
+
+// Write exports the result in the given format (`table` or `json`)
+func (r *Result) Write(w io.Writer, format string) error {
+	switch strings.ToLower(format) {
+	case "table":
+		return r.writeTable(w)
+	case "json":
+		return r.writeJSON(w)
+	default:
+		return fmt.Errorf("unknown query result format %q (expected table or json)", format)
+	}
+}
+
+func (r *Result) writeJSON(w io.Writer) error {
+	record := resultRecord{Kind: r.Kind, Count: r.Len(), Truncated: r.Truncated}
+	for _, node := range r.Nodes {
+		record.Nodes = append(record.Nodes, newNodeRecord(node))
+	}
+	for _, edge := range r.Edges {
+		record.Edges = append(record.Edges, newEdgeRecord(edge))
+	}
+	for _, path := range r.Paths {
+		record.Paths = append(record.Paths, pathNames(path))
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(record)
+}
+
+func (r *Result) writeTable(w io.Writer) error {
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	switch r.Kind {
+	case NodesQuery:
+		fmt.Fprintln(table, "NAME\tSTACK\tBACKGROUND\tATTRIBUTES\tTAGS")
+		for _, node := range r.Nodes {
+			record := newNodeRecord(node)
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%s\n", record.Name, record.Stack, record.Background,
+				strings.Join(record.Attributes, ","), strings.Join(record.Tags, ","))
+		}
+	case EdgesQuery:
+		fmt.Fprintln(table, "FROM\tTO\tATTRIBUTES\tTAGS")
+		for _, edge := range r.Edges {
+			record := newEdgeRecord(edge)
+			fmt.Fprintf(table, "%s\t%s\t%s\t%s\n", record.From, record.To,
+				strings.Join(record.Attributes, ","), strings.Join(record.Tags, ","))
+		}
+	default:
+		fmt.Fprintln(table, "LENGTH\tPATH")
+		for _, path := range r.Paths {
+			fmt.Fprintf(table, "%d\t%s\n", len(path)-1, strings.Join(pathNames(path), " -> "))
+		}
+	}
+
+	if err := table.Flush(); err != nil {
+		return err
+	}
+
+	summary := fmt.Sprintf("%d %s", r.Len(), r.Kind)
+	if r.Truncated {
+		summary += " (limit reached)"
+	}
+	_, err := fmt.Fprintln(w, summary)
+	return err
+}
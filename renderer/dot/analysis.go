@@ -8,6 +8,25 @@ import (
 	"github.com/glthr/DeMystify/common"
 )
 
+// analysisStyle is the style of the nodes with an attribute computed by the analyses
+type analysisStyle struct {
+	attribute   common.NodeAttribute
+	fillColor   string
+	borderColor string
+	penWidth    float64
+	description string // tooltip and legend
+}
+
+// analysisStyles are ordered by priority: a node takes the style of its first attribute
+var analysisStyles = []analysisStyle{
+	{common.IsIsolated, "#FFFF99", "", 0, "Isolated node"},
+	{common.IsSoftLock, "#FF8A80", "#B71C1C", 2, "Soft-lock (no goal card can be reached from it)"},
+	{common.IsSink, "#FFA07A", "#FF4500", 1.5, "Sink node (no outgoing connections)"},
+	{common.IsSource, "#98FB98", "#228B22", 1.5, "Source node (no incoming connections)"},
+	{common.IsCutOnly, "#F8BBD0", "#AD1457", 1.5, "Reachable only through disabled links or virtual cards"},
+	{common.IsUnreachable, "#CFD8DC", "#546E7A", 1.5, "Unreachable from the entry card"},
+}
+
 // applyAnalysisStyles applies styles based on graph analysis
 func (g *Generator) applyAnalysisStyles() error {
 	applyStyle := func(id int64, fillColor, borderColor, tooltip string, penWidth float64) error {
@@ -35,17 +54,12 @@ func (g *Generator) applyAnalysisStyles() error {
 	}
 
 	for _, node := range g.metadata.Nodes {
-		if node.IsOfType(common.IsIsolated) {
-			if err := applyStyle(node.GraphID, "#FFFF99", "", "Isolated node", 0); err != nil {
-				return err
-			}
-		} else if node.IsOfType(common.IsSink) {
-			if err := applyStyle(node.GraphID, "#FFA07A", "#FF4500", "Sink node (no outgoing connections)", 1.5); err != nil {
-				return err
-			}
-		} else if node.IsOfType(common.IsSource) {
-			if err := applyStyle(node.GraphID, "#98FB98", "#228B22", "Source node (no incoming connections)", 1.5); err != nil {
-				return err
+		for _, analysis := range analysisStyles {
+			if node.IsOfType(analysis.attribute) {
+				if err := applyStyle(node.GraphID, analysis.fillColor, analysis.borderColor, analysis.description, analysis.penWidth); err != nil {
+					return err
+				}
+				break
 			}
 		}
 	}
@@ -98,6 +112,52 @@ func (g *Generator) assignStackColors() {
 	}
 }
 
+// assignCommunityColors assigns colors to the communities
+// NOTE: the communities outnumber the stack colors, hence the hues evenly spread
+// on the color wheel (pastel tones, to keep the labels readable)
+func (g *Generator) assignCommunityColors() {
+	count := len(g.metadata.Stats.Communities.Communities)
+
+	for community := range count {
+		fillColor := pastelColor(float64(community) / float64(count))
+		g.communityColors[community] = nodeColors{
+			fillColor:   fillColor,
+			borderColor: darkenColor(fillColor),
+		}
+	}
+}
+
+// pastelColor returns a pastel color with the given hue (in [0, 1))
+func pastelColor(hue float64) string {
+	// HSV to RGB, with a low saturation and a high value
+	const saturation, value = 0.3, 1.0
+
+	h := hue * 6
+	sector := int(h) % 6
+	f := h - float64(int(h))
+	p := value * (1 - saturation)
+	q := value * (1 - saturation*f)
+	t := value * (1 - saturation*(1-f))
+
+	var r, gr, b float64
+	switch sector {
+	case 0:
+		r, gr, b = value, t, p
+	case 1:
+		r, gr, b = q, value, p
+	case 2:
+		r, gr, b = p, value, t
+	case 3:
+		r, gr, b = p, q, value
+	case 4:
+		r, gr, b = t, p, value
+	default:
+		r, gr, b = value, p, q
+	}
+
+	return fmt.Sprintf("#%02X%02X%02X", int(r*255), int(gr*255), int(b*255))
+}
+
 // darkenColor darkens a node color to use it as a border color
 func darkenColor(originalColor string) string {
 	parseComponent := func(hex string) (int64, error) {
@@ -2,6 +2,8 @@ package dot
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/glthr/DeMystify/common"
 )
@@ -114,6 +116,8 @@ func (g *Generator) applyBaseEdgeStyle(edge *common.Edge, style *edgeStyle) {
 		}
 	}
 
+	g.applyEdgeTagStyles(edge, style)
+
 	if edge.IsOfType(common.Disabled) {
 		if !edge.IsOfType(common.RestrictiveTransitivityTail) && !edge.IsOfType(common.RestrictiveTransitivityHead) {
 			style.style = "dashed"
@@ -133,6 +137,36 @@ func (g *Generator) applyBaseEdgeStyle(edge *common.Edge, style *edgeStyle) {
 	}
 }
 
+// applyEdgeTagStyles applies the styles of the edge custom tags
+// (when several tags are styled, the last one takes precedence)
+func (g *Generator) applyEdgeTagStyles(edge *common.Edge, style *edgeStyle) {
+	for _, tag := range edge.Tags {
+		tagStyle, exists := g.config.TagStyles[tag]
+		if !exists {
+			continue
+		}
+
+		if tagStyle.Color != "" {
+			style.color = tagStyle.Color
+		}
+		if tagStyle.Style != "" {
+			style.style = tagStyle.Style
+		}
+		if tagStyle.PenWidth > 0 {
+			style.penWidth = tagStyle.PenWidth
+		}
+	}
+
+	if len(edge.Tags) > 0 {
+		tags := strings.Join(edge.Tags, ", ")
+		if style.tooltip != "" {
+			style.tooltip += fmt.Sprintf(" [%s]", tags)
+		} else {
+			style.tooltip = tags
+		}
+	}
+}
+
 // edgeAttributesMatch checks if two edges match in terms of their attributes
 func (g *Generator) edgeAttributesMatch(edge1, edge2 *common.Edge) bool {
 	isEdge1Transitive := edge1.IsOfType(common.RestrictiveTransitivityTail) || edge1.IsOfType(common.RestrictiveTransitivityHead)
@@ -157,6 +191,10 @@ func (g *Generator) edgeAttributesMatch(edge1, edge2 *common.Edge) bool {
 		return false
 	}
 
+	if !slices.Equal(edge1.Tags, edge2.Tags) {
+		return false // differently tagged edges are rendered separately
+	}
+
 	attrMap1 := make(map[common.EdgeAttribute]bool, len(edge1.Attributes))
 	for _, attr := range edge1.Attributes {
 		attrMap1[attr] = true
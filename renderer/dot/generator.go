@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
 	"github.com/glthr/DeMystify/graph"
 )
 
@@ -14,11 +15,37 @@ type Config struct {
 	ColorByStack     bool
 	HighlightSinks   bool
 	HighlightSources bool
+	// styles of the custom node and edge tags (from the rules file)
+	TagStyles map[string]config.Style
+	// human-readable stack names (from the game profile)
+	StackDisplayNames map[string]string
+	// ColorByCommunity colors the cards by community instead of by stack
+	ColorByCommunity bool
+	// ScaleByCentrality scales the node labels by the given centrality metric
+	// (no scaling if empty)
+	ScaleByCentrality common.CentralityMetric
+	// RenderCondensation draws the condensation DAG of the strongly connected
+	// components instead of the cards
+	RenderCondensation bool
+	// RenderPositions draws the position-level graph (see graph.ReconstructPositions)
+	// instead of the cards
+	RenderPositions bool
+	// RenderAgeQuotient draws the Age-level quotient graph (see graph.ComputeAgeQuotient)
+	// instead of the cards
+	RenderAgeQuotient bool
+	// Overlays highlight paths (e.g., the cycles, see CycleOverlays) on the graph
+	Overlays []Overlay
+	// DominatorTree draws the given dominator tree instead of the graph (if not nil)
+	DominatorTree *graph.DominatorTree
+	// Subgraph restricts the rendering to the given subgraph (if not nil): its boundary
+	// edges are drawn as stubs (see graph.ExtractNeighbourhood and graph.ExtractStacks)
+	Subgraph *graph.Subgraph
 }
 
 func DefaultConfig() Config {
 	return Config{
 		UseNodeNames:     true,
+		ShowLegend:       true,
 		IncludeAnalysis:  true,
 		ColorByStack:     true,
 		HighlightSinks:   true,
@@ -26,15 +53,16 @@ func DefaultConfig() Config {
 	}
 }
 
-// NewGenerator creates a new DOT generator with the provided graph and metadata
-func NewGenerator(graph *graph.MystGraph, metadata *common.Metadata) *Generator {
+// NewGenerator creates a new DOT generator with the provided graph, metadata, and configuration
+func NewGenerator(graph *graph.MystGraph, metadata *common.Metadata, config Config) *Generator {
 	generator := &Generator{
 		graph:             graph,
 		metadata:          metadata,
-		config:            DefaultConfig(),
+		config:            config,
 		nodeStyles:        make(map[int64]nodeStyle),
 		edgeStyles:        make(map[string]edgeStyle),
 		stackColors:       make(map[string]nodeColors),
+		communityColors:   make(map[int]nodeColors),
 		customPathEdgeSet: make(map[string]bool),
 	}
 
@@ -46,6 +74,7 @@ func NewGenerator(graph *graph.MystGraph, metadata *common.Metadata) *Generator
 func (g *Generator) initialize() {
 	g.initializeEdgesMap()
 	g.initializeCustomPath()
+	g.initializeOverlays()
 }
 
 // initializeEdgesMap builds the internal edge map from metadata.Edges
@@ -57,6 +86,10 @@ func (g *Generator) initializeEdgesMap() {
 		fromID := edge.Source.GraphID
 		toID := edge.Target.GraphID
 
+		if !g.isRendered(fromID) || !g.isRendered(toID) {
+			continue
+		}
+
 		if edgesMap[fromID] == nil {
 			edgesMap[fromID] = make(map[int64][]common.Edge)
 		}
@@ -92,12 +125,32 @@ func (g *Generator) Generate(path []int64) (string, error) {
 		g.assignStackColors()
 	}
 
+	if g.config.ColorByCommunity && len(g.communityColors) == 0 {
+		g.assignCommunityColors()
+	}
+
+	if g.config.RenderCondensation {
+		return g.buildCondensationDOT()
+	}
+
+	if g.config.RenderPositions {
+		return g.buildPositionsDOT()
+	}
+
+	if g.config.RenderAgeQuotient {
+		return g.buildAgeQuotientDOT()
+	}
+
 	if g.config.IncludeAnalysis {
 		if err := g.applyAnalysisStyles(); err != nil {
 			return "", err
 		}
 	}
 
+	if g.config.DominatorTree != nil {
+		return g.buildDominatorTreeDOT()
+	}
+
 	dotStr, err := g.buildDOT()
 	if err != nil {
 		return "", err
@@ -106,6 +159,11 @@ func (g *Generator) Generate(path []int64) (string, error) {
 	return dotStr, nil
 }
 
+// isRendered reports whether the node belongs to the rendered (sub)graph
+func (g *Generator) isRendered(id int64) bool {
+	return g.config.Subgraph == nil || g.config.Subgraph.Contains(id)
+}
+
 func (g *Generator) isOnCustomPath(fromID, toID int64) bool {
 	return g.customPathEdgeSet[fmt.Sprintf("%d->%d", fromID, toID)]
 }
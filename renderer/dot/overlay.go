@@ -0,0 +1,127 @@
+package dot
+
+import (
+	"fmt"
+
+	"github.com/glthr/DeMystify/common"
+	"github.com/glthr/DeMystify/config"
+)
+
+// Overlay highlights paths (e.g., cycles) and nodes on top of the graph rendering
+type Overlay struct {
+	Name  string
+	Paths [][]int64
+	// Nodes are highlighted with the border color and pen width of the style
+	Nodes []int64
+	// Closed links the last node of each path back to its first node (cycles)
+	Closed bool
+	// Style of the edges of the paths (color, style, and pen width)
+	Style config.Style
+}
+
+// cycleOverlayStyles are the styles of the cycle overlays, per cycle kind
+var cycleOverlayStyles = map[common.CycleKind]config.Style{
+	common.RotationLoop:    {Color: "#1E88E5", PenWidth: 2},
+	common.CorridorLoop:    {Color: "#43A047", PenWidth: 2},
+	common.ExplorationLoop: {Color: "#FB8C00", Style: "dashed", PenWidth: 2},
+}
+
+// CycleOverlays returns an overlay per cycle kind (all kinds if none is given)
+func CycleOverlays(cycles []common.CycleInfo, kinds ...common.CycleKind) []Overlay {
+	if len(kinds) == 0 {
+		kinds = []common.CycleKind{common.RotationLoop, common.CorridorLoop, common.ExplorationLoop}
+	}
+
+	var overlays []Overlay
+	for _, kind := range kinds {
+		overlay := Overlay{
+			Name:   fmt.Sprintf("%s loops", kind),
+			Closed: true,
+			Style:  cycleOverlayStyles[kind],
+		}
+
+		for _, cycle := range cycles {
+			if cycle.Kind == kind {
+				overlay.Paths = append(overlay.Paths, cycle.Nodes)
+			}
+		}
+
+		if len(overlay.Paths) > 0 {
+			overlays = append(overlays, overlay)
+		}
+	}
+
+	return overlays
+}
+
+// initializeOverlays indexes the overlay edges and nodes for fast lookups
+// (when an edge or a node belongs to several overlays, the last one takes precedence)
+func (g *Generator) initializeOverlays() {
+	g.overlayEdges = make(map[string]*Overlay)
+	g.overlayNodes = make(map[int64]*Overlay)
+
+	for i := range g.config.Overlays {
+		overlay := &g.config.Overlays[i]
+		for _, id := range overlay.Nodes {
+			g.overlayNodes[id] = overlay
+		}
+		for _, path := range overlay.Paths {
+			for j := range path {
+				if j == len(path)-1 && !overlay.Closed {
+					break
+				}
+				next := path[(j+1)%len(path)]
+				g.overlayEdges[fmt.Sprintf("%d->%d", path[j], next)] = overlay
+			}
+		}
+	}
+}
+
+// applyOverlayStyle styles an edge belonging to an overlay
+// (bidirectional edges are matched in both directions)
+func (g *Generator) applyOverlayStyle(fromID, toID int64, style *edgeStyle) {
+	overlay, exists := g.overlayEdges[fmt.Sprintf("%d->%d", fromID, toID)]
+	if !exists && style.bidirectional {
+		overlay, exists = g.overlayEdges[fmt.Sprintf("%d->%d", toID, fromID)]
+	}
+	if !exists {
+		return
+	}
+
+	if overlay.Style.Color != "" {
+		style.color = overlay.Style.Color
+	}
+	if overlay.Style.Style != "" {
+		style.style = overlay.Style.Style
+	}
+	if overlay.Style.PenWidth > 0 {
+		style.penWidth = overlay.Style.PenWidth
+	}
+
+	if style.tooltip == "" {
+		style.tooltip = overlay.Name
+	} else {
+		style.tooltip = fmt.Sprintf("%s (%s)", style.tooltip, overlay.Name)
+	}
+}
+
+// applyOverlayNodeStyle styles a node belonging to an overlay
+func (g *Generator) applyOverlayNodeStyle(id int64, style *nodeStyle) {
+	overlay, exists := g.overlayNodes[id]
+	if !exists {
+		return
+	}
+
+	if overlay.Style.Color != "" {
+		style.borderColor = overlay.Style.Color
+	}
+	if overlay.Style.PenWidth > 0 {
+		style.penWidth = overlay.Style.PenWidth
+	}
+
+	if style.tooltip == "" {
+		style.tooltip = overlay.Name
+	} else {
+		style.tooltip = fmt.Sprintf("%s (%s)", style.tooltip, overlay.Name)
+	}
+}
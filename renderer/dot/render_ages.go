@@ -0,0 +1,103 @@
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// buildAgeQuotientDOT generates the DOT representation of the Age-level quotient graph:
+// one node per stack, and one edge per pair of linked stacks, labeled with its number
+// of links and their attributes
+func (g *Generator) buildAgeQuotientDOT() (string, error) {
+	quotient := g.metadata.Stats.AgeQuotient
+
+	var buf bytes.Buffer
+
+	buf.WriteString("/* Generated with DeMystify (github.com/glthr/DeMystify) */\n\n")
+
+	buf.WriteString("digraph G {\n")
+
+	g.writeGraphAttributes(&buf)
+
+	buf.WriteString("  // Stacks\n")
+	for _, stack := range quotient.Stacks {
+		style := nodeStyle{penWidth: 2}
+		if color, exists := g.stackColors[stack]; exists {
+			style.fillColor = color.fillColor
+			style.borderColor = color.borderColor
+		}
+
+		name := stack
+		if displayName, ok := g.config.StackDisplayNames[stack]; ok {
+			name = displayName
+		}
+		style.label = fmt.Sprintf("%s\\n%d nodes", escapeForDOT(name), quotient.Cards[stack])
+
+		buf.WriteString(fmt.Sprintf("  \"%s\" [%s, fontsize=14];\n", escapeForDOT(stack), g.buildNodeStyleString(style)))
+	}
+
+	buf.WriteString("\n  // Links between stacks\n")
+	for _, link := range quotient.Links {
+		style := edgeStyle{
+			color:    "#000000",
+			penWidth: 0.6 + 0.2*float64(min(link.Count, 20)),
+			tooltip: fmt.Sprintf("exit: %s; entry: %s",
+				formatNodeInfos(link.ExitCards), formatNodeInfos(link.EntryCards)),
+		}
+		if color, exists := g.stackColors[link.From]; exists {
+			style.color = color.borderColor
+		}
+		if link.AttributeCounts[common.Disabled.String()] == link.Count {
+			style.style = "dashed"
+		}
+
+		buf.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [%s, label=\"%s\", fontsize=9];\n",
+			escapeForDOT(link.From), escapeForDOT(link.To), g.buildEdgeStyleString(style), ageLinkLabel(link)))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String(), nil
+}
+
+// ageLinkLabel describes the number of links, and the number of disabled,
+// not implemented, and transitive ones
+func ageLinkLabel(link common.AgeLink) string {
+	label := fmt.Sprintf("%d link(s)", link.Count)
+
+	transitive := link.AttributeCounts[common.RestrictiveTransitivityTail.String()] +
+		link.AttributeCounts[common.RestrictiveTransitivityHead.String()]
+
+	var details []string
+	for _, detail := range []struct {
+		name  string
+		count int
+	}{
+		{"disabled", link.AttributeCounts[common.Disabled.String()]},
+		{"not implemented", link.AttributeCounts[common.NotImplemented.String()]},
+		{"transitive", transitive},
+	} {
+		if detail.count > 0 {
+			details = append(details, fmt.Sprintf("%d %s", detail.count, detail.name))
+		}
+	}
+
+	if len(details) > 0 {
+		label += "\\n" + strings.Join(details, ", ")
+	}
+
+	return label
+}
+
+func formatNodeInfos(nodes []common.NodeInfo) string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,79 @@
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// buildCondensationDOT generates the DOT representation of the condensation DAG:
+// one node per strongly connected component, traps highlighted
+func (g *Generator) buildCondensationDOT() (string, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("/* Generated with DeMystify (github.com/glthr/DeMystify) */\n\n")
+
+	buf.WriteString("digraph G {\n")
+
+	g.writeGraphAttributes(&buf)
+
+	buf.WriteString("  // Strongly connected components\n")
+	for _, scc := range g.metadata.Stats.StronglyConnectedComponents {
+		buf.WriteString(fmt.Sprintf("  \"scc_%d\" [%s];\n", scc.ID, g.buildNodeStyleString(g.getComponentStyle(scc))))
+	}
+
+	buf.WriteString("\n  // Condensation edges\n")
+	for _, edge := range g.metadata.Stats.CondensationEdges {
+		style := edgeStyle{color: "#000000"}
+		if edge.Count > 1 {
+			style.tooltip = fmt.Sprintf("%d edges", edge.Count)
+			style.penWidth = 0.6 + 0.2*float64(min(edge.Count, 20))
+		}
+		buf.WriteString(fmt.Sprintf("  \"scc_%d\" -> \"scc_%d\" [%s];\n", edge.From, edge.To, g.buildEdgeStyleString(style)))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String(), nil
+}
+
+// getComponentStyle creates the style of a condensation node
+func (g *Generator) getComponentStyle(scc common.SCCInfo) nodeStyle {
+	style := nodeStyle{}
+
+	// the singletons are labeled with the name of their node
+	var title string
+	if len(scc.Nodes) == 1 {
+		title = escapeForDOT(g.graph.GetNameForID(scc.Nodes[0]))
+	} else {
+		title = fmt.Sprintf("SCC %d (%d nodes)", scc.ID, len(scc.Nodes))
+	}
+
+	stacks := make([]string, 0, len(scc.Stacks))
+	for _, stack := range scc.Stacks {
+		if displayName, ok := g.config.StackDisplayNames[stack]; ok {
+			stack = displayName
+		}
+		stacks = append(stacks, escapeForDOT(stack))
+	}
+	style.label = fmt.Sprintf("%s\\n%s", title, strings.Join(stacks, ", "))
+
+	// components spanning a single stack take the stack color
+	if g.config.ColorByStack && len(scc.Stacks) == 1 {
+		if color, exists := g.stackColors[scc.Stacks[0]]; exists {
+			style.fillColor = color.fillColor
+			style.borderColor = color.borderColor
+		}
+	}
+
+	if scc.IsTrap {
+		style.fillColor = "#FFA07A"
+		style.borderColor = "#FF4500"
+		style.penWidth = 2.5
+		style.tooltip = "Trap (no edge leaves the component)"
+	}
+
+	return style
+}
@@ -13,9 +13,17 @@ func (g *Generator) buildDOT() (string, error) {
 	buf.WriteString("digraph G {\n")
 
 	g.writeGraphAttributes(&buf)
-	g.writeComponentClusters(&buf)
+	if g.config.Subgraph == nil {
+		g.writeComponentClusters(&buf)
+	}
 	g.writeNodes(&buf)
 	g.writeEdges(&buf)
+	if g.config.Subgraph != nil {
+		g.writeBoundaryStubs(&buf)
+	}
+	if g.config.ShowLegend && g.config.IncludeAnalysis {
+		g.writeLegend(&buf)
+	}
 
 	buf.WriteString("}\n")
 
@@ -0,0 +1,60 @@
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+)
+
+// buildDominatorTreeDOT generates the DOT representation of the dominator tree:
+// the cards reachable from the root, each one linked from its immediate dominator
+func (g *Generator) buildDominatorTreeDOT() (string, error) {
+	tree := g.config.DominatorTree
+
+	var buf bytes.Buffer
+
+	buf.WriteString("/* Generated with DeMystify (github.com/glthr/DeMystify) */\n\n")
+
+	buf.WriteString("digraph G {\n")
+
+	g.writeGraphAttributes(&buf)
+
+	edges := tree.Edges()
+
+	nodeIDs := []int64{tree.Root}
+	for _, edge := range edges {
+		nodeIDs = append(nodeIDs, edge[1])
+	}
+	slices.Sort(nodeIDs)
+
+	buf.WriteString("  // Nodes reachable from the root\n")
+	for _, id := range nodeIDs {
+		name, exists := g.graph.GetNodeName(id)
+		if !exists {
+			continue
+		}
+
+		nodeObj, nodeExists := g.graph.NodeMap[name]
+		if !nodeExists {
+			continue
+		}
+
+		styleStr := g.getNodeStyleString(id, nodeObj)
+		if id == tree.Root {
+			styleStr = g.applyCustomPathStylingToNode(styleStr, nodeObj, true)
+		}
+		styleStr = g.ensureNodeLabel(styleStr, nodeObj, id)
+
+		buf.WriteString(fmt.Sprintf("  \"%d\" [%s];\n", id, styleStr))
+	}
+
+	buf.WriteString("\n  // Immediate dominator -> dominated node\n")
+	for _, edge := range edges {
+		buf.WriteString(fmt.Sprintf("  \"%d\" -> \"%d\" [%s];\n",
+			edge[0], edge[1], g.buildEdgeStyleString(edgeStyle{color: "#000000"})))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String(), nil
+}
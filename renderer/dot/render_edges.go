@@ -337,6 +337,9 @@ func (g *Generator) getTransitiveEdgeKey(nodePairID string, edge *common.Edge) s
 // renderEdge renders an edge to the buffer
 func (g *Generator) renderEdge(buf *bytes.Buffer, fromID, toID int64, edge *common.Edge, isOnPath bool) {
 	style := g.applyEdgeStyle(edge, isOnPath)
+	if !isOnPath {
+		g.applyOverlayStyle(fromID, toID, &style)
+	}
 	styleStr := g.buildEdgeStyleString(style)
 	buf.WriteString(fmt.Sprintf("  \"%d\" -> \"%d\" [%s];\n", fromID, toID, styleStr))
 }
@@ -345,6 +348,7 @@ func (g *Generator) renderEdge(buf *bytes.Buffer, fromID, toID int64, edge *comm
 func (g *Generator) renderBidirectionalEdge(buf *bytes.Buffer, fromID, toID int64, edge *common.Edge) {
 	style := g.applyEdgeStyle(edge, false)
 	style.bidirectional = true
+	g.applyOverlayStyle(fromID, toID, &style)
 	styleStr := g.buildEdgeStyleString(style)
 	buf.WriteString(fmt.Sprintf("  \"%d\" -> \"%d\" [%s];\n", fromID, toID, styleStr))
 }
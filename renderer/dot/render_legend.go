@@ -0,0 +1,50 @@
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+)
+
+// writeLegend adds a legend of the analysis styles (see analysisStyles), with the number
+// of nodes of each
+// NOTE: the styles matching no node are omitted
+func (g *Generator) writeLegend(buf *bytes.Buffer) {
+	counts := make([]int, len(analysisStyles))
+	for _, node := range g.metadata.Nodes {
+		if !g.isRendered(node.GraphID) {
+			continue
+		}
+
+		for i, analysis := range analysisStyles {
+			if node.IsOfType(analysis.attribute) {
+				counts[i]++
+				break
+			}
+		}
+	}
+
+	var rows bytes.Buffer
+	for i, analysis := range analysisStyles {
+		if counts[i] == 0 {
+			continue
+		}
+
+		borderColor := analysis.borderColor
+		if borderColor == "" {
+			borderColor = analysis.fillColor
+		}
+
+		rows.WriteString(fmt.Sprintf(`<TR><TD BGCOLOR="%s" COLOR="%s" WIDTH="20"></TD><TD ALIGN="LEFT">%s (%d)</TD></TR>`,
+			analysis.fillColor, borderColor, html.EscapeString(analysis.description), counts[i]))
+	}
+
+	if rows.Len() == 0 {
+		return
+	}
+
+	buf.WriteString("\n  // Legend\n")
+	buf.WriteString(fmt.Sprintf(
+		"  \"__LEGEND__\" [shape=plaintext, style=\"\", fontsize=10, label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"2\"><TR><TD COLSPAN=\"2\"><B>Legend</B></TD></TR>%s</TABLE>>];\n",
+		rows.String()))
+}
@@ -20,7 +20,9 @@ func (g *Generator) writeNodes(buf *bytes.Buffer) {
 	var nodeIDs []int64
 	nodes := g.graph.Nodes()
 	for nodes.Next() {
-		nodeIDs = append(nodeIDs, nodes.Node().ID())
+		if g.isRendered(nodes.Node().ID()) {
+			nodeIDs = append(nodeIDs, nodes.Node().ID())
+		}
 	}
 
 	// sort node IDs for deterministic ordering
@@ -28,6 +30,8 @@ func (g *Generator) writeNodes(buf *bytes.Buffer) {
 		return nodeIDs[i] < nodeIDs[j]
 	})
 
+	fontSizes := g.getCentralityFontSizes()
+
 	// process nodes in sorted order
 	for _, id := range nodeIDs {
 		name, exists := g.graph.GetNodeName(id)
@@ -55,10 +59,44 @@ func (g *Generator) writeNodes(buf *bytes.Buffer) {
 		// add label if not already present
 		styleStr = g.ensureNodeLabel(styleStr, nodeObj, id)
 
+		if fontSize, ok := fontSizes[id]; ok {
+			styleStr += fmt.Sprintf(", fontsize=%.1f", fontSize)
+		}
+
 		buf.WriteString(fmt.Sprintf("  \"%d\" [%s];\n", id, styleStr))
 	}
 }
 
+// font sizes of the nodes scaled by centrality
+const (
+	minCentralityFontSize = 10.0
+	maxCentralityFontSize = 40.0
+)
+
+// getCentralityFontSizes scales the font size of the nodes (hence their size) linearly
+// with the configured centrality metric
+func (g *Generator) getCentralityFontSizes() map[int64]float64 {
+	metric := g.config.ScaleByCentrality
+	if metric == "" {
+		return nil
+	}
+
+	maxValue := 0.0
+	for _, c := range g.metadata.Stats.Centrality.Nodes {
+		maxValue = max(maxValue, c.Value(metric))
+	}
+
+	if maxValue <= 0 {
+		return nil
+	}
+
+	fontSizes := make(map[int64]float64, len(g.metadata.Stats.Centrality.Nodes))
+	for id, c := range g.metadata.Stats.Centrality.Nodes {
+		fontSizes[id] = minCentralityFontSize + (maxCentralityFontSize-minCentralityFontSize)*c.Value(metric)/maxValue
+	}
+	return fontSizes
+}
+
 // getDisplayName creates a readable display name for a node
 func (g *Generator) getDisplayName(node common.Node) string {
 	name := node.Name
@@ -68,6 +106,9 @@ func (g *Generator) getDisplayName(node common.Node) string {
 	}
 
 	if node.IsOfType(common.IsStack) {
+		if displayName, ok := g.config.StackDisplayNames[node.Name]; ok && displayName != node.Name {
+			name = fmt.Sprintf("%s (%s)", name, displayName)
+		}
 		name = fmt.Sprintf("[Stack] %s", name)
 	}
 
@@ -80,10 +121,17 @@ func (g *Generator) getDisplayName(node common.Node) string {
 
 // getNodeStyleString gets the style string for a node
 func (g *Generator) getNodeStyleString(id int64, node common.Node) string {
-	if style, hasStyle := g.nodeStyles[id]; hasStyle {
-		return g.buildNodeStyleString(style)
+	style, hasStyle := g.nodeStyles[id]
+	if !hasStyle {
+		style = g.getDefaultNodeStyle(node)
 	}
+	g.applyOverlayNodeStyle(id, &style)
 
+	return g.buildNodeStyleString(style)
+}
+
+// getDefaultNodeStyle returns the style of a node that has no analysis style
+func (g *Generator) getDefaultNodeStyle(node common.Node) nodeStyle {
 	// create a default style
 	style := nodeStyle{}
 
@@ -103,17 +151,27 @@ func (g *Generator) getNodeStyleString(id int64, node common.Node) string {
 			style.borderColor = "#dedbdb"
 		}
 
-		// apply stack-based colors if enabled
-		if g.config.ColorByStack {
-			if color, exists := g.stackColors[node.StackName]; exists {
-				style.fillColor = color.fillColor
+		// apply community-based or stack-based colors if enabled
+		var color nodeColors
+		var hasColor bool
+		if g.config.ColorByCommunity {
+			if community, exists := g.metadata.Stats.Communities.NodeCommunity[node.GraphID]; exists {
+				color, hasColor = g.communityColors[community]
+			}
+		} else if g.config.ColorByStack {
+			color, hasColor = g.stackColors[node.StackName]
+		}
+
+		if hasColor {
+			style.fillColor = color.fillColor
 
-				if style.borderColor == "" {
-					style.borderColor = color.borderColor
-				}
+			if style.borderColor == "" {
+				style.borderColor = color.borderColor
 			}
 		}
 
+		g.applyNodeTagStyles(node, &style)
+
 		// add secondary name (if available)
 		name := g.getDisplayName(node)
 		if node.SecondaryName != nil {
@@ -125,7 +183,41 @@ func (g *Generator) getNodeStyleString(id int64, node common.Node) string {
 		style.borderColor = "#fbc02d"
 	}
 
-	return g.buildNodeStyleString(style)
+	return style
+}
+
+// applyNodeTagStyles applies the styles of the node custom tags
+// (when several tags are styled, the last one takes precedence)
+func (g *Generator) applyNodeTagStyles(node common.Node, style *nodeStyle) {
+	for _, tag := range node.Tags {
+		tagStyle, exists := g.config.TagStyles[tag]
+		if !exists {
+			continue
+		}
+
+		if tagStyle.FillColor != "" {
+			style.fillColor = tagStyle.FillColor
+		}
+		if tagStyle.Color != "" {
+			style.borderColor = tagStyle.Color
+		}
+		if tagStyle.FontColor != "" {
+			style.fontColor = tagStyle.FontColor
+		}
+		if tagStyle.Shape != "" {
+			style.shape = tagStyle.Shape
+		}
+		if tagStyle.Style != "" {
+			style.style = tagStyle.Style
+		}
+		if tagStyle.PenWidth > 0 {
+			style.penWidth = tagStyle.PenWidth
+		}
+	}
+
+	if len(node.Tags) > 0 {
+		style.tooltip = strings.Join(node.Tags, ", ")
+	}
 }
 
 // applyCustomPathStylingToNode enhances style for nodes on the custom path
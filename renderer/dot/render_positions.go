@@ -0,0 +1,74 @@
+package dot
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// buildPositionsDOT generates the DOT representation of the position-level graph:
+// one node per physical position (the cards facing the different directions from a spot)
+func (g *Generator) buildPositionsDOT() (string, error) {
+	var buf bytes.Buffer
+
+	buf.WriteString("/* Generated with DeMystify (github.com/glthr/DeMystify) */\n\n")
+
+	buf.WriteString("digraph G {\n")
+
+	g.writeGraphAttributes(&buf)
+
+	positions := g.metadata.Stats.Positions
+
+	buf.WriteString("  // Positions\n")
+	for _, position := range positions.Positions {
+		buf.WriteString(fmt.Sprintf("  \"position_%d\" [%s];\n", position.ID, g.buildNodeStyleString(g.getPositionStyle(position))))
+	}
+
+	buf.WriteString("\n  // Position edges\n")
+	for _, edge := range positions.Edges {
+		style := edgeStyle{color: "#000000"}
+		if g.stackOfPosition(edge.From) != g.stackOfPosition(edge.To) {
+			style.style = "dashed"
+			style.tooltip = "CrossAge connection"
+		}
+		buf.WriteString(fmt.Sprintf("  \"position_%d\" -> \"position_%d\" [%s];\n", edge.From, edge.To, g.buildEdgeStyleString(style)))
+	}
+
+	buf.WriteString("}\n")
+
+	return buf.String(), nil
+}
+
+// getPositionStyle creates the style of a position node
+func (g *Generator) getPositionStyle(position common.PositionInfo) nodeStyle {
+	style := nodeStyle{}
+
+	names := make([]string, 0, len(position.Cards))
+	for _, id := range position.Cards {
+		names = append(names, escapeForDOT(g.graph.GetNameForID(id)))
+	}
+
+	if len(position.Cards) == 1 {
+		style.label = names[0]
+	} else {
+		style.label = fmt.Sprintf("%s\\n(%d views)", names[0], len(position.Cards))
+		style.tooltip = fmt.Sprintf("%s: %s", strings.Join(names, ", "), strings.Join(position.Evidence, ", "))
+		style.penWidth = 2
+	}
+
+	if g.config.ColorByStack {
+		if color, exists := g.stackColors[position.Stack]; exists {
+			style.fillColor = color.fillColor
+			style.borderColor = color.borderColor
+		}
+	}
+
+	return style
+}
+
+// stackOfPosition returns the stack of a position
+func (g *Generator) stackOfPosition(id int) string {
+	return g.metadata.Stats.Positions.Positions[id].Stack
+}
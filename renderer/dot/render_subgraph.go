@@ -0,0 +1,50 @@
+package dot
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// writeBoundaryStubs draws the edges crossing the boundary of the subgraph as stubs:
+// a dashed edge to (or from) a label naming the card outside the subgraph
+// NOTE: a single stub is drawn for each pair of nodes
+func (g *Generator) writeBoundaryStubs(buf *bytes.Buffer) {
+	buf.WriteString("  // Boundary edges (stubs)\n")
+
+	stubs := make(map[string]bool)
+	for _, edge := range g.config.Subgraph.BoundaryEdges {
+		fromID, toID := edge.Source.GraphID, edge.Target.GraphID
+		stubID := fmt.Sprintf("stub:%d->%d", fromID, toID)
+		if stubs[stubID] {
+			continue
+		}
+		stubs[stubID] = true
+
+		outsideID := toID
+		if g.isRendered(toID) {
+			outsideID = fromID
+		}
+
+		label := g.graph.GetNameForID(outsideID)
+		if node, exists := g.graph.NodeMap[label]; exists {
+			label = g.getDisplayName(node)
+		}
+
+		buf.WriteString(fmt.Sprintf("  \"%s\" [shape=plaintext, style=\"\", fontcolor=\"#7F8C8D\", label=\"%s\"];\n",
+			stubID, escapeForDOT(label)))
+
+		style := g.applyEdgeStyle(&edge, false)
+		style.style = "dashed"
+		if style.tooltip == "" {
+			style.tooltip = "Boundary edge"
+		}
+
+		from, to := fmt.Sprintf("%d", fromID), fmt.Sprintf("%d", toID)
+		if outsideID == fromID {
+			from = stubID
+		} else {
+			to = stubID
+		}
+		buf.WriteString(fmt.Sprintf("  \"%s\" -> \"%s\" [%s];\n", from, to, g.buildEdgeStyleString(style)))
+	}
+}
@@ -13,11 +13,17 @@ type Generator struct {
 	nodeStyles  map[int64]nodeStyle
 	edgeStyles  map[string]edgeStyle
 	stackColors map[string]nodeColors
-	customPath  []int64
+	// community ID -> colors (see Config.ColorByCommunity)
+	communityColors map[int]nodeColors
+	customPath      []int64
 	// from -> to -> edges
 	edgesMap map[int64]map[int64][]common.Edge
 	// pre-calculated custom path edges for fast lookup
 	customPathEdgeSet map[string]bool
+	// pre-calculated overlay edges ("from->to") for fast lookup
+	overlayEdges map[string]*Overlay
+	// pre-calculated overlay nodes for fast lookup
+	overlayNodes map[int64]*Overlay
 }
 
 // nodeStyle contains styling attributes for a node
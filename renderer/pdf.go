@@ -4,13 +4,16 @@ import (
 	"fmt"
 	"log"
 	"os/exec"
+	"path/filepath"
+	"strings"
 )
 
-const pdfFilePath = "generated/graph.pdf"
-
-// RenderPDF generates a PDF visualization using Neato
+// RenderPDF generates a PDF visualization using Neato, next to the DOT file
+// (e.g., `generated/graph.dot` -> `generated/graph.pdf`)
 // NOTE: it would be great to replace this system call with a function call (library)
 func RenderPDF(dotFilePath string) {
+	pdfFilePath := strings.TrimSuffix(dotFilePath, filepath.Ext(dotFilePath)) + ".pdf"
+
 	cmd := exec.Command(
 		"neato",
 		"-Tpdf",
@@ -0,0 +1,277 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/glthr/DeMystify/common"
+)
+
+// maxListedNodes is the maximum number of center and periphery nodes listed per scope
+// in the Markdown and HTML reports (the JSON report lists them all)
+const maxListedNodes = 10
+
+// section is a part of the report (the whole graph, or a stack), made of tables
+type section struct {
+	Title  string
+	Tables []table
+}
+
+type table struct {
+	Title  string
+	Header []string
+	Rows   [][]string
+}
+
+// WriteMetrics exports the metrics report in the given format (`md`, `html`, or `json`)
+func WriteMetrics(w io.Writer, metrics common.MetricsReport, format string) error {
+	switch strings.ToLower(format) {
+	case "md", "markdown":
+		return writeMarkdown(w, metricsSections(metrics))
+	case "html":
+		return writeHTML(w, metricsSections(metrics))
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(newMetricsRecord(metrics))
+	default:
+		return fmt.Errorf("unknown metrics report format %q (expected md, html, or json)", format)
+	}
+}
+
+// metricsSections lays out the metrics of the whole graph, then of each stack
+func metricsSections(metrics common.MetricsReport) []section {
+	sections := []section{scopeSection("Whole Graph", metrics.Graph)}
+	for _, stack := range metrics.Stacks {
+		sections = append(sections, scopeSection("Stack: "+stack.Stack, stack))
+	}
+	return sections
+}
+
+func scopeSection(title string, metrics common.GraphMetrics) section {
+	summary := table{
+		Title:  "Summary",
+		Header: []string{"Metric", "Value"},
+		Rows: [][]string{
+			{"Nodes", strconv.Itoa(metrics.Nodes)},
+			{"Edges", strconv.Itoa(metrics.Edges)},
+			{"Density", formatRatio(metrics.Density)},
+			{"Reciprocity", formatRatio(metrics.Reciprocity)},
+			{"Average clustering coefficient", formatRatio(metrics.AverageClustering)},
+			{"Degree assortativity (out/in)", formatRatio(metrics.Assortativity)},
+			{"Largest strongly connected component", strconv.Itoa(metrics.LargestComponent)},
+			{"Diameter", formatValue(metrics.Diameter)},
+			{"Radius", formatValue(metrics.Radius)},
+			{"Center", formatNodes(metrics.Center)},
+			{"Periphery", formatNodes(metrics.Periphery)},
+		},
+	}
+
+	eccentricities := table{Title: "Eccentricity Distribution", Header: []string{"Eccentricity", "Nodes"}}
+	for _, bin := range metrics.EccentricityDistribution {
+		eccentricities.Rows = append(eccentricities.Rows, []string{formatValue(bin.Value), strconv.Itoa(bin.Count)})
+	}
+
+	// in-degrees and out-degrees side by side
+	degrees := table{Title: "Degree Distribution", Header: []string{"Degree", "Nodes (in)", "Nodes (out)"}}
+	inCounts, outCounts := make(map[float64]int), make(map[float64]int)
+	var values []float64
+	for _, bin := range metrics.InDegreeDistribution {
+		inCounts[bin.Value] = bin.Count
+		values = append(values, bin.Value)
+	}
+	for _, bin := range metrics.OutDegreeDistribution {
+		outCounts[bin.Value] = bin.Count
+		if _, exists := inCounts[bin.Value]; !exists {
+			values = append(values, bin.Value)
+		}
+	}
+	slices.Sort(values)
+	for _, value := range values {
+		degrees.Rows = append(degrees.Rows, []string{formatValue(value), strconv.Itoa(inCounts[value]), strconv.Itoa(outCounts[value])})
+	}
+
+	nodeAttributes := table{Title: "Node Attributes", Header: []string{"Attribute", "Nodes"}}
+	for _, count := range metrics.NodeAttributes {
+		nodeAttributes.Rows = append(nodeAttributes.Rows, []string{count.Attribute, strconv.Itoa(count.Count)})
+	}
+
+	edgeAttributes := table{Title: "Link Attributes", Header: []string{"Attribute", "Links"}}
+	for _, count := range metrics.EdgeAttributes {
+		edgeAttributes.Rows = append(edgeAttributes.Rows, []string{count.Attribute, strconv.Itoa(count.Count)})
+	}
+
+	return section{
+		Title:  title,
+		Tables: []table{summary, eccentricities, degrees, nodeAttributes, edgeAttributes},
+	}
+}
+
+func formatRatio(value float64) string {
+	return strconv.FormatFloat(value, 'f', 4, 64)
+}
+
+func formatValue(value float64) string {
+	return strconv.FormatFloat(value, 'g', 4, 64)
+}
+
+// formatNodes lists the names of the nodes (up to maxListedNodes)
+func formatNodes(nodes []common.NodeInfo) string {
+	var names []string
+	for i, node := range nodes {
+		if i == maxListedNodes {
+			names = append(names, fmt.Sprintf("and %d more", len(nodes)-maxListedNodes))
+			break
+		}
+		names = append(names, node.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func writeMarkdown(w io.Writer, sections []section) error {
+	var b strings.Builder
+	b.WriteString("# Myst Graph Metrics\n")
+
+	escape := strings.NewReplacer("|", `\|`).Replace
+	for _, s := range sections {
+		fmt.Fprintf(&b, "\n## %s\n", s.Title)
+		for _, t := range s.Tables {
+			fmt.Fprintf(&b, "\n### %s\n\n", t.Title)
+			fmt.Fprintf(&b, "| %s |\n", strings.Join(t.Header, " | "))
+			fmt.Fprintf(&b, "|%s\n", strings.Repeat(" --- |", len(t.Header)))
+			for _, row := range t.Rows {
+				cells := make([]string, len(row))
+				for i, cell := range row {
+					cells[i] = escape(cell)
+				}
+				fmt.Fprintf(&b, "| %s |\n", strings.Join(cells, " | "))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+var htmlTemplate = template.Must(template.New("metrics").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Myst Graph Metrics</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1em; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; }
+th { background: #f5f5f5; }
+</style>
+</head>
+<body>
+<h1>Myst Graph Metrics</h1>
+{{- range .}}
+<h2>{{.Title}}</h2>
+{{- range .Tables}}
+<h3>{{.Title}}</h3>
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{- range .Rows}}
+<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+func writeHTML(w io.Writer, sections []section) error {
+	return htmlTemplate.Execute(w, sections)
+}
+
+// metricsRecord is the exported representation of the metrics report
+type metricsRecord struct {
+	Graph  scopeRecord   `json:"graph"`
+	Stacks []scopeRecord `json:"stacks"`
+}
+
+type scopeRecord struct {
+	Stack                    string            `json:"stack,omitempty"`
+	Nodes                    int               `json:"nodes"`
+	Edges                    int               `json:"edges"`
+	Density                  float64           `json:"density"`
+	Reciprocity              float64           `json:"reciprocity"`
+	AverageClustering        float64           `json:"averageClustering"`
+	Assortativity            float64           `json:"assortativity"`
+	LargestComponent         int               `json:"largestComponent"`
+	Diameter                 float64           `json:"diameter"`
+	Radius                   float64           `json:"radius"`
+	EccentricityDistribution []histogramRecord `json:"eccentricityDistribution"`
+	Center                   []string          `json:"center"`
+	Periphery                []string          `json:"periphery"`
+	InDegreeDistribution     []histogramRecord `json:"inDegreeDistribution"`
+	OutDegreeDistribution    []histogramRecord `json:"outDegreeDistribution"`
+	NodeAttributes           map[string]int    `json:"nodeAttributes"`
+	EdgeAttributes           map[string]int    `json:"edgeAttributes"`
+}
+
+type histogramRecord struct {
+	Value float64 `json:"value"`
+	Count int     `json:"count"`
+}
+
+func newMetricsRecord(metrics common.MetricsReport) metricsRecord {
+	record := metricsRecord{Graph: newScopeRecord(metrics.Graph), Stacks: make([]scopeRecord, 0, len(metrics.Stacks))}
+	for _, stack := range metrics.Stacks {
+		record.Stacks = append(record.Stacks, newScopeRecord(stack))
+	}
+	return record
+}
+
+func newScopeRecord(metrics common.GraphMetrics) scopeRecord {
+	record := scopeRecord{
+		Stack:                    metrics.Stack,
+		Nodes:                    metrics.Nodes,
+		Edges:                    metrics.Edges,
+		Density:                  metrics.Density,
+		Reciprocity:              metrics.Reciprocity,
+		AverageClustering:        metrics.AverageClustering,
+		Assortativity:            metrics.Assortativity,
+		LargestComponent:         metrics.LargestComponent,
+		Diameter:                 metrics.Diameter,
+		Radius:                   metrics.Radius,
+		EccentricityDistribution: newHistogramRecords(metrics.EccentricityDistribution),
+		Center:                   nodeNames(metrics.Center),
+		Periphery:                nodeNames(metrics.Periphery),
+		InDegreeDistribution:     newHistogramRecords(metrics.InDegreeDistribution),
+		OutDegreeDistribution:    newHistogramRecords(metrics.OutDegreeDistribution),
+		NodeAttributes:           make(map[string]int, len(metrics.NodeAttributes)),
+		EdgeAttributes:           make(map[string]int, len(metrics.EdgeAttributes)),
+	}
+	for _, count := range metrics.NodeAttributes {
+		record.NodeAttributes[count.Attribute] = count.Count
+	}
+	for _, count := range metrics.EdgeAttributes {
+		record.EdgeAttributes[count.Attribute] = count.Count
+	}
+	return record
+}
+
+func newHistogramRecords(bins []common.HistogramBin) []histogramRecord {
+	records := make([]histogramRecord, 0, len(bins))
+	for _, bin := range bins {
+		records = append(records, histogramRecord{Value: bin.Value, Count: bin.Count})
+	}
+	return records
+}
+
+func nodeNames(nodes []common.NodeInfo) []string {
+	names := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		names = append(names, node.Name)
+	}
+	return names
+}
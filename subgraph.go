@@ -0,0 +1,82 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/glthr/DeMystify/config"
+	"github.com/glthr/DeMystify/graph"
+	"github.com/glthr/DeMystify/renderer/dot"
+)
+
+// runSubgraph renders a part of the Myst Graph: the neighbourhood of a card (ego graph),
+// or some stacks
+func runSubgraph(args []string) {
+	flags := flag.NewFlagSet(subgraphCommand, flag.ExitOnError)
+	graphOptions := registerGraphFlags(flags)
+	center := flags.String("node", "", "render the neighbourhood of the card `{stack}:{card id}`")
+	hops := flags.Int("hops", 2, "maximum number of edges between the card and its neighbours")
+	directionName := flags.String("direction", string(graph.BothDirections), "direction of the edges to the neighbours: `out`, `in`, or `both`")
+	var stacks []string
+	flags.Func("stack", "render the `stack`, repeatable (the union of the stacks is rendered)", func(value string) error {
+		stacks = append(stacks, value)
+		return nil
+	})
+	_ = flags.Parse(args)
+
+	if flags.NArg() < 1 || (*center == "") == (len(stacks) == 0) {
+		log.Fatal("Usage: go run . subgraph [-profile <name|profile.json>] [-rules <rules.json>] [-continue-on-error] [-cost-model <preset>] [-no-cache] (-node <stack:id> [-hops <n>] [-direction out|in|both] | -stack <stack>...) <xml_hypercard_files_directory_path>")
+	}
+
+	direction, ok := graph.ParseDirection(*directionName)
+	if !ok {
+		log.Fatalf("unknown direction: %s", *directionName)
+	}
+
+	if err := CheckNeatoInstalled(); err != nil {
+		log.Fatalf("Neato is not installed: %v", err)
+	}
+
+	loaded := loadGraph(flags.Arg(0), graphOptions, graph.DefaultCycleOptions())
+	g := loaded.graph
+
+	var subgraph *graph.Subgraph
+	if *center != "" {
+		ref, err := config.ParseCardRef(*center)
+		if err != nil {
+			log.Fatalf("invalid card: %v", err)
+		}
+
+		centerID, err := g.GetCardNodeID(ref)
+		if err != nil {
+			log.Fatalf("unknown card: %v", err)
+		}
+
+		subgraph, err = g.ExtractNeighbourhood(centerID, *hops, direction)
+		if err != nil {
+			log.Fatalf("unable to extract the neighbourhood: %v", err)
+		}
+	} else {
+		var err error
+		subgraph, err = g.ExtractStacks(stacks...)
+		if err != nil {
+			log.Fatalf("unable to extract the stacks: %v", err)
+		}
+	}
+
+	fmt.Printf("Subgraph: %d node(s), %d boundary edge(s)\n", len(subgraph.NodeIDs()), len(subgraph.BoundaryEdges))
+	fmt.Println("Generating the subgraph...")
+
+	dotConfig := dot.DefaultConfig()
+	dotConfig.TagStyles = loaded.profile.Rules.Styles
+	dotConfig.StackDisplayNames = loaded.profile.StackDisplayNames
+	dotConfig.Subgraph = subgraph
+
+	RenderAlternateGraph(g, loaded.metadata, dotConfig, subgraphPath)
+
+	if loaded.parseErrors.HasErrors() {
+		os.Exit(exitPartialSuccess)
+	}
+}